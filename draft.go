@@ -0,0 +1,140 @@
+package boba
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Draft persists the values of a Form to a JSON file keyed by component Id, so that
+// users do not lose their progress when they quit before submitting
+type Draft struct {
+	path     string
+	saved    []byte     // The contents last written to disk, used to skip redundant writes
+	savedSeq int        // The seq of the save that wrote the saved contents
+	loading  bool       // Whether the draft is being read into the form, which pauses saving
+	seq      int        // Incremented by every save so that older writes never replace newer ones
+	mu       sync.Mutex // Guards written, the saves run in parallel commands
+	written  int        // The seq of the last save that reached the disk
+}
+
+// Creates a draft that is stored at the provided path
+func NewDraft(path string) *Draft {
+	return &Draft{path: path}
+}
+
+// Sent once a save has been written, the draft only records the contents as saved then
+type draftSavedMsg struct {
+	draft *Draft
+	seq   int
+	data  []byte
+}
+
+// Wraps the result of loading the draft into a form, see Form.UpdateDraft
+type draftLoadedMsg struct {
+	draft *Draft
+	msg   tea.Msg
+}
+
+// Returns a command that reads the draft and prefills the form via the SetValuesMsg.
+// Should be sequenced after the StartMsg, which resets the form. A missing draft
+// is not an error
+func (d *Draft) Load() tea.Cmd {
+	return func() tea.Msg {
+		data, err := os.ReadFile(d.path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return ErrMsg{err}
+		}
+
+		var values map[string]any
+		if err := json.Unmarshal(data, &values); err != nil {
+			return ErrMsg{err}
+		}
+
+		return SetValuesMsg{Values: values}
+	}
+}
+
+// Returns a command that writes the values of the form to the draft if they have
+// changed since the last save. Meant to be called after the form is updated, see
+// Form.UpdateDraft, which also records the contents as saved once the write succeeds
+func (d *Draft) Save(f Form) tea.Cmd {
+	data, err := json.Marshal(f.Values())
+	if err != nil {
+		return func() tea.Msg { return ErrMsg{err} }
+	}
+	if string(data) == string(d.saved) {
+		return nil
+	}
+	d.seq++
+	seq := d.seq
+
+	return func() tea.Msg {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if seq < d.written { // A newer save has already been written
+			return nil
+		}
+		if err := replaceFile(d.path, data); err != nil {
+			return ErrMsg{err}
+		}
+		d.written = seq
+		return draftSavedMsg{draft: d, seq: seq, data: data}
+	}
+}
+
+// Writes the data to a temporary file and renames it over the file at the path, so that
+// the file is never left partially written. Used by the Draft and the History
+func replaceFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once the file has been renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Records the contents of the save as written, ignoring results older than the latest one
+func (d *Draft) saveResult(msg draftSavedMsg) {
+	if msg.seq < d.savedSeq {
+		return
+	}
+	d.saved = msg.data
+	d.savedSeq = msg.seq
+}
+
+// Deletes the draft, for instance once the form has been submitted
+func (d *Draft) Remove() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.saved = nil
+	d.seq++
+	d.written = d.seq // Saves that are still running no longer write the draft
+	d.savedSeq = d.seq
+	err := os.Remove(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,459 @@
+package boba
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A Matcher decides whether an option's label matches the filter text. It returns a score
+// that is used to rank the results (higher is better) along with the indexes of the
+// matched runes in the label, which are highlighted in the view
+type Matcher interface {
+	Match(query string, target string) (score int, matches []int, ok bool)
+}
+
+// Adapts a function to the Matcher interface
+type MatcherFunc func(query string, target string) (score int, matches []int, ok bool)
+
+func (f MatcherFunc) Match(query string, target string) (int, []int, bool) {
+	return f(query, target)
+}
+
+// Implemented by the built-in matchers, which run against text that is prepared once
+// rather than on every filter pass
+type textMatcher interface {
+	matchText(query matchText, target matchText) (int, []int, bool)
+}
+
+// Matches labels that contain the filter text, ignoring case. All matches share the same
+// score, so the original order of the options is kept
+var ContainsMatcher Matcher = containsMatcher{}
+
+type containsMatcher struct{}
+
+func (containsMatcher) Match(query string, target string) (int, []int, bool) {
+	return matchContains(newMatchText(query), newMatchText(target))
+}
+
+func (containsMatcher) matchText(query matchText, target matchText) (int, []int, bool) {
+	return matchContains(query, target)
+}
+
+func matchContains(query matchText, target matchText) (int, []int, bool) {
+	q, t := query.lower, target.lower
+	for start := 0; start+len(q) <= len(t); start++ {
+		if slices.Equal(t[start:start+len(q)], q) {
+			matches := make([]int, len(q))
+			for i := range q {
+				matches[i] = start + i
+			}
+			return 0, matches, true
+		}
+	}
+	return 0, nil, false
+}
+
+// Scoring used by the FuzzyMatcher
+const (
+	fuzzyMatchScore       = 16
+	fuzzyConsecutiveBonus = 8
+	fuzzyBoundaryBonus    = 10
+	fuzzyGapPenalty       = 1
+)
+
+// Matches labels that contain every character of the filter text in order, ignoring
+// case, similar to fzf. Consecutive characters and characters at the start of words
+// score higher, while gaps between the matched characters score lower
+var FuzzyMatcher Matcher = fuzzyMatcher{}
+
+type fuzzyMatcher struct{}
+
+func (fuzzyMatcher) Match(query string, target string) (int, []int, bool) {
+	return matchFuzzy(newMatchText(query), newMatchText(target))
+}
+
+func (fuzzyMatcher) matchText(query matchText, target matchText) (int, []int, bool) {
+	return matchFuzzy(query, target)
+}
+
+func matchFuzzy(query matchText, target matchText) (int, []int, bool) {
+	q, t := query.lower, target.lower
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+
+	bestScore, found := 0, false
+	var bestMatches []int
+
+	// Try every occurrence of the first character as a starting point and keep the best
+	for start := range t {
+		if t[start] != q[0] {
+			continue
+		}
+
+		score, matches, ok := fuzzyMatchFrom(q, t, target.runes, start)
+		if ok && (!found || score > bestScore) {
+			bestScore, bestMatches, found = score, matches, true
+		}
+	}
+
+	return bestScore, bestMatches, found
+}
+
+// Greedily matches the query against the target beginning at the start index
+func fuzzyMatchFrom(q []rune, t []rune, original []rune, start int) (int, []int, bool) {
+	matches := make([]int, 0, len(q))
+	score := 0
+	qi := 0
+	for ti := start; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		score += fuzzyMatchScore
+		if isWordBoundary(original, ti) {
+			score += fuzzyBoundaryBonus
+		}
+		if len(matches) > 0 {
+			gap := ti - matches[len(matches)-1] - 1
+			if gap == 0 {
+				score += fuzzyConsecutiveBonus
+			}
+			score -= gap * fuzzyGapPenalty
+		}
+
+		matches = append(matches, ti)
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, nil, false
+	}
+
+	return score - start*fuzzyGapPenalty, matches, true
+}
+
+// Indicates whether the rune at i starts a new word, either after a separator or at
+// a change from lower to upper case
+func isWordBoundary(r []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := r[i-1]
+	if unicode.IsLower(prev) && unicode.IsUpper(r[i]) {
+		return true
+	}
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev)
+}
+
+// Lowercases each rune individually, so that the indexes match the original string
+func toLowerRunes(s string) []rune {
+	r := []rune(s)
+	for i := range r {
+		r[i] = unicode.ToLower(r[i])
+	}
+	return r
+}
+
+// Text that is prepared for matching once, rather than on every filter pass
+type matchText struct {
+	text  string
+	runes []rune
+	lower []rune
+}
+
+func newMatchText(s string) matchText {
+	return matchText{text: s, runes: []rune(s), lower: toLowerRunes(s)}
+}
+
+// Resolves the matcher to one that runs against prepared text. The built-in matchers use
+// the precomputed lowercase text, while custom matchers are passed the original strings
+func resolveMatcher(matcher Matcher) func(query matchText, target matchText) (int, []int, bool) {
+	switch m := matcher.(type) {
+	case nil:
+		return matchContains
+	case textMatcher:
+		return m.matchText
+	}
+	return func(query matchText, target matchText) (int, []int, bool) {
+		return matcher.Match(query.text, target.text)
+	}
+}
+
+// The text that an option is filtered by. Matches in the label are highlighted, while
+// the extra text (e.g. descriptions and keywords) is only searched
+type filterTarget struct {
+	label matchText
+	extra []matchText
+	boost int // Added to the score of a match, e.g. for options that were chosen recently
+}
+
+func newFilterTarget(label string, description string, keywords []string) filterTarget {
+	target := filterTarget{label: newMatchText(label)}
+	for _, extra := range append([]string{description}, keywords...) {
+		if extra != "" {
+			target.extra = append(target.extra, newMatchText(extra))
+		}
+	}
+	return target
+}
+
+// Runs the matcher against every target and returns the indexes of the matching targets
+// along with the positions of the runes matched in their labels. Label matches are ranked
+// ahead of matches in the extra text, and then by score. An empty query matches everything,
+// in which case only boosted targets are moved ahead
+func matchAll(query string, targets []filterTarget, matcher Matcher) ([]int, [][]int) {
+	if query == "" {
+		indexes := make([]int, len(targets))
+		for i := range targets {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return targets[indexes[a]].boost > targets[indexes[b]].boost
+		})
+		return indexes, make([][]int, len(targets))
+	}
+
+	match := resolveMatcher(matcher)
+	q := newMatchText(query)
+
+	type result struct {
+		index   int
+		score   int
+		matches []int
+		extra   bool // Whether the match was only found in the extra text
+	}
+
+	var results []result
+	for i, target := range targets {
+		if score, matches, ok := match(q, target.label); ok {
+			results = append(results, result{index: i, score: score + target.boost, matches: matches})
+			continue
+		}
+		for _, extra := range target.extra {
+			if score, _, ok := match(q, extra); ok {
+				results = append(results, result{index: i, score: score + target.boost, extra: true})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		if results[a].extra != results[b].extra {
+			return !results[a].extra
+		}
+		return results[a].score > results[b].score
+	})
+
+	indexes := make([]int, len(results))
+	matches := make([][]int, len(results))
+	for i, r := range results {
+		indexes[i] = r.index
+		matches[i] = r.matches
+	}
+	return indexes, matches
+}
+
+const (
+	defaultAsyncFilterThreshold = 10000                 // Options are filtered in the background once a selector has more than this many
+	defaultFilterDebounce       = 50 * time.Millisecond // How long the filter text must stop changing before the options are filtered
+)
+
+// Used to tell apart the filter results of different selectors in the same program
+var filterIds atomic.Int64
+
+// Fired once the filter text has stopped changing for the debounce duration
+type filterDebounceMsg struct {
+	id  int64
+	seq int
+}
+
+// Contains the results of a filter pass that ran in the background
+type filterResultMsg struct {
+	id      int64
+	seq     int
+	indexes []int
+	matches [][]int
+}
+
+// Caches the prepared text of the options along with the results of the last filter pass,
+// so that the options are only filtered again once the filter text or the options change.
+// Long lists are filtered in the background, and the results of stale passes are dropped.
+// The filter text is only matched once it stops changing for the debounce duration
+type optionFilter struct {
+	id        int64
+	matcher   Matcher
+	threshold int
+	debounce  time.Duration
+	typed     string // The filter text that is waiting for the debounce
+	typedSeq  int
+	targets   []filterTarget
+	groups    []string
+	leading   []string // Groups that are placed ahead of the others
+	query     string
+	seq       int
+	stale     bool
+	pending   bool
+	indexes   []int   // Indexes of the matching options, grouped and ranked
+	matches   [][]int // Positions of the runes matched in the label of each matching option
+}
+
+func newOptionFilter(opts FilterOpts) optionFilter {
+	threshold := opts.AsyncThreshold
+	if threshold == 0 {
+		threshold = defaultAsyncFilterThreshold
+	}
+	debounce := opts.Debounce
+	if debounce == 0 {
+		debounce = defaultFilterDebounce
+	}
+	return optionFilter{id: filterIds.Add(1), matcher: opts.Matcher, threshold: threshold, debounce: debounce}
+}
+
+// Replaces the text of the options, which are filtered again on the next pass. Results
+// for the previous options are dropped, since their indexes no longer apply
+func (f *optionFilter) setTargets(targets []filterTarget, groups []string) {
+	f.targets, f.groups = targets, groups
+	f.indexes, f.matches = nil, nil
+	f.stale = true
+	f.seq++ // Drops the results of any pass that is still running
+}
+
+// Filters the options if the query or the options changed since the last pass. Returns
+// whether new results are available, or a command when waiting for the filter text to stop
+// changing or filtering in the background. Clearing the filter text takes effect at once
+func (f *optionFilter) update(query string) (bool, tea.Cmd) {
+	if query != "" && query != f.query && f.debounce > 0 {
+		return false, f.wait(query)
+	}
+	return f.run(query)
+}
+
+// Waits for the debounce duration before filtering by the query, unless already waiting for it
+func (f *optionFilter) wait(query string) tea.Cmd {
+	if query == f.typed {
+		return nil
+	}
+	f.typed = query
+	f.typedSeq++
+	id, seq := f.id, f.typedSeq
+	return tea.Tick(f.debounce, func(time.Time) tea.Msg {
+		return filterDebounceMsg{id: id, seq: seq}
+	})
+}
+
+// Filters the options by the query, in the background for long lists
+func (f *optionFilter) run(query string) (bool, tea.Cmd) {
+	if f.typed != "" {
+		f.typed = ""
+		f.typedSeq++ // Drops the debounce that is still waiting
+	}
+	if !f.stale && query == f.query {
+		return false, nil
+	}
+	f.stale, f.query = false, query
+	f.seq++
+
+	targets, groups, leading, matcher := f.targets, f.groups, f.leading, f.matcher
+	if query == "" || len(targets) <= f.threshold { // Nothing needs to be matched for an empty query
+		f.indexes, f.matches = filterGroups(query, targets, groups, leading, matcher)
+		f.pending = false
+		return true, nil
+	}
+
+	f.pending = true
+	id, seq := f.id, f.seq
+	return false, func() tea.Msg {
+		indexes, matches := filterGroups(query, targets, groups, leading, matcher)
+		return filterResultMsg{id: id, seq: seq, indexes: indexes, matches: matches}
+	}
+}
+
+// Filters the options once the debounce of the latest filter text fires, and stores the
+// results of a background pass. Returns whether new results are available
+func (f *optionFilter) receive(msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case filterDebounceMsg:
+		if msg.id != f.id || msg.seq != f.typedSeq {
+			return false, nil
+		}
+		return f.run(f.typed)
+	case filterResultMsg:
+		if msg.id != f.id || msg.seq != f.seq {
+			return false, nil
+		}
+		f.indexes, f.matches = msg.indexes, msg.matches
+		f.pending = false
+		return true, nil
+	}
+	return false, nil
+}
+
+// Matches the targets against the query and places options in the same group together
+func filterGroups(query string, targets []filterTarget, groups []string, leading []string, matcher Matcher) ([]int, [][]int) {
+	indexes, matches := matchAll(query, targets, matcher)
+	return groupIndexes(indexes, matches, groups, leading...)
+}
+
+// Returns the matched positions of the option at index i, if the options have been filtered
+func matchesAt(matches [][]int, i int) []int {
+	if i >= len(matches) {
+		return nil
+	}
+	return matches[i]
+}
+
+// Renders the text in the provided color, with the matched runes in the primary color
+func highlight(text string, matches []int, theme Theme, color ColorType) string {
+	if len(matches) == 0 {
+		return theme.Color(text, color)
+	}
+
+	matched := make(map[int]bool, len(matches))
+	for _, i := range matches {
+		matched[i] = true
+	}
+
+	base := strings.Builder{}
+	runes := []rune(text)
+	for start := 0; start < len(runes); { // Color runs of matched and unmatched runes together
+		end := start
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		if matched[start] {
+			base.WriteString(theme.Color(string(runes[start:end]), Primary))
+		} else {
+			base.WriteString(theme.Color(string(runes[start:end]), color))
+		}
+		start = end
+	}
+
+	return base.String()
+}
+
+// Renders an option's icon, its label with the matched runes highlighted, and its
+// description in a dimmed color
+func renderOption(icon string, label string, description string, matches []int, theme Theme, color ColorType) string {
+	base := strings.Builder{}
+	if icon != "" {
+		base.WriteString(theme.Color(icon, color) + " ")
+	}
+	base.WriteString(highlight(label, matches, theme, color))
+	if description != "" {
+		base.WriteString("  " + theme.Color(description, Neutral))
+	}
+	return base.String()
+}
+
+// Renders the row that offers to add the filter text as a new option
+func renderCreate(text string, theme Theme) string {
+	return fmt.Sprintf("%s %s\n", theme.Color(">", Primary), theme.Color(fmt.Sprintf("Create %q", text), Primary))
+}
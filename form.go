@@ -1,7 +1,12 @@
 package boba
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Form is a utility for wrapping multiple components which allows for cycling
@@ -17,7 +22,10 @@ func (f Form) getPrevComponent(msg ComponentBackMsg) ComponentModel {
 	i := findIndex(f, func(c ComponentModel) bool {
 		return c.Id() == msg.ComponentName
 	})
-	return f[i-1]
+	if i == -1 {
+		return nil
+	}
+	return f.get(f.nearest(i-1, Up))
 }
 
 // Navigates to the next component in the form, if possible
@@ -25,31 +33,192 @@ func (f Form) getNextComponent(msg ComponentNextMsg) ComponentModel {
 	i := findIndex(f, func(c ComponentModel) bool {
 		return c.Id() == msg.ComponentName
 	})
-	return f[i+1]
+	if i == -1 {
+		return nil
+	}
+	return f.get(f.nearest(i+1, Down))
+}
+
+// Focuses the component again when there is no other component to move focus to, so that
+// the form does not lose focus at its first or last component
+func (f Form) refocus(id string) tea.Cmd {
+	i := findIndex(f, func(c ComponentModel) bool {
+		return c.Id() == id
+	})
+	if i == -1 {
+		return nil
+	}
+	return f[i].Focus()
+}
+
+// Returns the component at the given index, or nil when out of range
+func (f Form) get(i int) ComponentModel {
+	if i < 0 || i >= len(f) {
+		return nil
+	}
+	return f[i]
+}
+
+// Returns the index of the closest focusable component starting at i and moving in
+// the given direction, or -1 if there is none
+func (f Form) nearest(i int, direction Direction) int {
+	step := 1
+	if direction == Up {
+		step = -1
+	}
+	for ; i >= 0 && i < len(f); i += step {
+		if focusable(f[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Indicates whether the component can receive focus, section headers and disabled
+// fields are skipped
+func focusable(c ComponentModel) bool {
+	if _, isSection := c.(*FormSection); isSection {
+		return false
+	}
+	field, ok := c.(Field)
+	return !ok || !field.Disabled()
 }
 
-// Focuses on the first component in the form
+// Resets the form and focuses on the first component in the form
 func (f Form) focus() tea.Cmd {
-	var cmds []tea.Cmd
-	for _, c := range f[0:] {
-		c.Blur()
+	f.Reset()
+	return f.focusAt(f.nearest(0, Down))
+}
+
+// Restores every component to its initial value. Components that do not implement
+// the Resettable interface are cleared instead
+func (f Form) Reset() {
+	for _, c := range f {
+		if r, ok := c.(Resettable); ok {
+			r.Reset()
+		} else {
+			c.Clear()
+		}
+	}
+}
+
+// Empties the value of every component in the form
+func (f Form) Clear() {
+	for _, c := range f {
 		c.Clear()
 	}
-	cmds = append(cmds, f[0].Focus())
-	return tea.Batch(cmds...)
+}
+
+// Sets the values of the components in the form, keyed by their Id. Values for
+// unknown components are ignored
+func (f Form) SetValues(values map[string]any) error {
+	var errs []error
+	for _, c := range f {
+		v, ok := values[c.Id()]
+		if !ok {
+			continue
+		}
+		setter, ok := c.(ValueSetter)
+		if !ok {
+			errs = append(errs, fmt.Errorf("component %q does not support setting values", c.Id()))
+			continue
+		}
+		if err := setter.SetValue(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validates every enabled Field in the form, returning the combined errors
+func (f Form) Validate() error {
+	var errs []error
+	for _, c := range f {
+		if field, ok := c.(Field); ok && !field.Disabled() {
+			errs = append(errs, field.Validate())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Indicates whether any component in the form has changed from its initial value
+func (f Form) Dirty() bool {
+	return len(f.DirtyFields()) > 0
+}
+
+// Returns the Ids of the components whose values differ from their initial values
+func (f Form) DirtyFields() []string {
+	var ids []string
+	for _, c := range f {
+		if r, ok := c.(Resettable); ok && r.Dirty() {
+			ids = append(ids, c.Id())
+		}
+	}
+	return ids
+}
+
+// Blurs every component in the form and focuses the one at the given index
+func (f Form) focusAt(i int) tea.Cmd {
+	if i < 0 || i >= len(f) {
+		return nil
+	}
+	f.blur()
+	return f[i].Focus()
+}
+
+// Blurs every component in the form
+func (f Form) blur() {
+	for _, c := range f {
+		c.Blur()
+	}
+}
+
+// Indicates whether the component is the first focusable one in the form
+func (f Form) isFirst(id string) bool {
+	c := f.get(f.nearest(0, Down))
+	return c != nil && c.Id() == id
+}
+
+// Indicates whether the component is the last focusable one in the form
+func (f Form) isLast(id string) bool {
+	c := f.get(f.nearest(len(f)-1, Up))
+	return c != nil && c.Id() == id
+}
+
+// Returns the values of all components in the form, keyed by their Id
+func (f Form) Values() map[string]any {
+	values := make(map[string]any, len(f))
+	for _, c := range f {
+		if _, isSection := c.(*FormSection); !isSection {
+			values[c.Id()] = c.Value()
+		}
+	}
+	return values
 }
 
 func (f Form) Update(msg tea.Msg) (Form, tea.Cmd) {
 	var cmds []tea.Cmd
 	switch msg := msg.(type) {
 	case ComponentBackMsg:
-		c := f.getPrevComponent(msg)
-		cmds = append(cmds, c.Focus())
+		if c := f.getPrevComponent(msg); c != nil {
+			cmds = append(cmds, c.Focus())
+		} else {
+			cmds = append(cmds, f.refocus(msg.ComponentName))
+		}
 	case ComponentNextMsg:
-		c := f.getNextComponent(msg)
-		cmds = append(cmds, c.Focus())
+		if c := f.getNextComponent(msg); c != nil {
+			cmds = append(cmds, c.Focus())
+		} else {
+			cmds = append(cmds, f.refocus(msg.ComponentName))
+		}
 	case StartMsg:
 		cmds = append(cmds, f.focus()) // The StartMsg triggers focus in the form
+	case SetValuesMsg:
+		if err := f.SetValues(msg.Values); err != nil {
+			cmds = append(cmds, func() tea.Msg { return ErrMsg{err} })
+		}
+	case tea.MouseMsg:
+		return f.Mouse(msg, FormViewOpts{})
 	default:
 		for i, c := range f {
 			var cmd tea.Cmd
@@ -60,6 +229,207 @@ func (f Form) Update(msg tea.Msg) (Form, tea.Cmd) {
 	return f, tea.Batch(cmds...)
 }
 
+// Updates the form like Update and keeps the draft in sync with it. The StartMsg prefills
+// the form from the draft once it has been reset, and every change after that is saved
+// to the draft. Forms that should not be persisted use Update instead
+func (f Form) UpdateDraft(msg tea.Msg, d *Draft) (Form, tea.Cmd) {
+	switch msg := msg.(type) {
+	case StartMsg:
+		f, cmd := f.Update(msg)
+		d.loading = true // Saving the reset form would overwrite the draft before it is read
+		load := d.Load()
+		return f, tea.Batch(cmd, func() tea.Msg {
+			return draftLoadedMsg{draft: d, msg: load()}
+		})
+	case draftLoadedMsg:
+		if msg.draft != d {
+			break
+		}
+		d.loading = false
+		if msg.msg == nil {
+			return f, nil
+		}
+		if _, ok := msg.msg.(SetValuesMsg); !ok {
+			return f, func() tea.Msg { return msg.msg } // Errors are passed on to the parent
+		}
+		return f.Update(msg.msg)
+	case draftSavedMsg:
+		if msg.draft == d {
+			d.saveResult(msg)
+			return f, nil
+		}
+	}
+
+	f, cmd := f.Update(msg)
+	if d.loading {
+		return f, cmd
+	}
+	return f, tea.Batch(cmd, d.Save(f))
+}
+
+// Determines how the form arranges labels relative to their fields
+type FormLayout string
+
+const (
+	Horizontal FormLayout = "horizontal" // Labels are aligned in a column beside their fields
+	Vertical   FormLayout = "vertical"   // Labels are rendered above their fields
+)
+
+type FormViewOpts struct {
+	Layout FormLayout
+	Width  func() int // When the horizontal layout does not fit the width, the vertical one is used instead
+	Theme  Theme
+}
+
+// Components that implement the formField interface are rendered by the form with
+// an aligned label and description. Other components are rendered via their View.
+type formField interface {
+	Label() string
+	Description() string
+	fieldView() string
+}
+
+// Renders the form with the default options
+func (f Form) View() string {
+	return f.Render(FormViewOpts{})
+}
+
+// Renders every component in the form, aligning the labels of the fields. Components
+// following a FormSection are indented beneath it.
+func (f Form) Render(opts FormViewOpts) string {
+	view, _ := f.render(opts)
+	return view
+}
+
+// Returns the regions that the components of the form are rendered to with the options,
+// relative to the top of the form. Labels and descriptions are not part of the regions
+func (f Form) Regions(opts FormViewOpts) []Region {
+	_, regions := f.render(opts)
+	views := make([]Region, len(regions))
+	for i, r := range regions {
+		views[i] = r.view
+	}
+	return views
+}
+
+// Handles a mouse event relative to the top of a form that is rendered with the options.
+// Clicking a component focuses it, and the event is passed on to the component under the
+// mouse relative to its own region. Forms that are rendered via their View receive mouse
+// events in their Update, others should pass them to Mouse with the same options instead
+func (f Form) Mouse(msg tea.MouseMsg, opts FormViewOpts) (Form, tea.Cmd) {
+	var cmds []tea.Cmd
+	_, regions := f.render(opts)
+	for i, r := range regions {
+		if msg.Y < r.start || msg.Y >= r.end {
+			continue
+		}
+		if clicked(msg) && focusable(f[i]) && !f[i].Focused() {
+			cmds = append(cmds, f.focusAt(i))
+		}
+		var cmd tea.Cmd
+		f[i], cmd = f[i].Update(r.view.Translate(msg))
+		cmds = append(cmds, cmd)
+	}
+	return f, tea.Batch(cmds...)
+}
+
+// The lines taken up by a component in the form, including its label and description,
+// and the region its own view is rendered to
+type formRegion struct {
+	start int // The first line of the component
+	end   int // The line after the last line of the component
+	view  Region
+}
+
+// Renders the form and records where each of its components was rendered
+func (f Form) render(opts FormViewOpts) (string, []formRegion) {
+	layout := opts.Layout
+	if layout == "" {
+		layout = Horizontal
+	}
+
+	labelWidth, fieldWidth := f.measure()
+	if layout == Horizontal && opts.Width != nil && labelWidth+fieldWidth+4 > opts.Width() {
+		layout = Vertical
+	}
+
+	base := strings.Builder{}
+	regions := make([]formRegion, len(f))
+	line := func() int { return strings.Count(base.String(), "\n") }
+	indent := ""
+	for i, c := range f {
+		start := line()
+		if _, ok := c.(*FormSection); ok {
+			if i > 0 {
+				base.WriteString("\n")
+			}
+			view := c.View()
+			regions[i].view = viewRegion(view, 0, line())
+			base.WriteString(view)
+			indent = "  "
+			regions[i].start, regions[i].end = start, line()
+			continue
+		}
+
+		field, ok := c.(formField)
+		if !ok {
+			view := c.View()
+			regions[i].view = viewRegion(view, len(indent), start)
+			base.WriteString(indent + view)
+			regions[i].start, regions[i].end = start, line()
+			continue
+		}
+
+		label := opts.Theme.ColorCond(field.Label(), Primary, c.Focused())
+		if v, ok := c.(Field); ok && v.Disabled() {
+			label = opts.Theme.Color(field.Label(), Neutral)
+		}
+		descriptionIndent := indent + "  "
+		if layout == Horizontal {
+			padding := strings.Repeat(" ", labelWidth-lipgloss.Width(field.Label()))
+			fieldIndent := indent + strings.Repeat(" ", labelWidth+2) // Aligns fields that span several lines
+			regions[i].view = viewRegion(field.fieldView(), len(fieldIndent), start)
+			fieldView := strings.ReplaceAll(field.fieldView(), "\n", "\n"+fieldIndent)
+			base.WriteString(fmt.Sprintf("%s%s%s  %s\n", indent, label, padding, fieldView))
+			descriptionIndent = indent + strings.Repeat(" ", labelWidth+4)
+		} else {
+			if field.Label() != "" {
+				base.WriteString(fmt.Sprintf("%s%s\n", indent, label))
+			}
+			regions[i].view = viewRegion(field.fieldView(), len(indent), line())
+			fieldView := strings.ReplaceAll(field.fieldView(), "\n", "\n"+indent)
+			base.WriteString(fmt.Sprintf("%s%s\n", indent, fieldView))
+		}
+
+		if field.Description() != "" {
+			base.WriteString(fmt.Sprintf("%s%s\n", descriptionIndent, opts.Theme.Color(field.Description(), Neutral)))
+		}
+		if v, ok := c.(Field); ok && v.Err() != nil {
+			base.WriteString(fmt.Sprintf("%s%s\n", descriptionIndent, opts.Theme.Color(v.Err().Error(), Error)))
+		}
+		regions[i].start, regions[i].end = start, line()
+	}
+
+	return base.String(), regions
+}
+
+// Returns the region taken up by the view when it is rendered at the position
+func viewRegion(view string, x int, y int) Region {
+	view = strings.TrimSuffix(view, "\n")
+	return Region{X: x, Y: y, Width: lipgloss.Width(view), Height: lipgloss.Height(view)}
+}
+
+// Returns the widest label and the widest field in the form
+func (f Form) measure() (labelWidth int, fieldWidth int) {
+	for _, c := range f {
+		if field, ok := c.(formField); ok {
+			labelWidth = max(labelWidth, lipgloss.Width(field.Label()))
+			fieldWidth = max(fieldWidth, lipgloss.Width(field.fieldView()))
+		}
+	}
+	return labelWidth, fieldWidth
+}
+
 func findIndex[T any](slice []T, predicate func(T) bool) int {
 	for i, v := range slice {
 		if predicate(v) {
@@ -0,0 +1,71 @@
+package boba
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A header that groups the components that follow it in a Form. Sections are
+// skipped when moving focus between the components of the form.
+type FormSection struct {
+	id          string
+	title       string
+	description string
+	theme       Theme
+}
+
+type NewFormSectionOpts struct {
+	Id          string
+	Title       string
+	Description string
+	Theme       Theme
+}
+
+// Creates a section header for use in a Form
+func NewFormSection(opts NewFormSectionOpts) ComponentModel {
+	return &FormSection{
+		id:          opts.Id,
+		title:       opts.Title,
+		description: opts.Description,
+		theme:       opts.Theme,
+	}
+}
+
+func (m FormSection) Init() tea.Cmd {
+	return nil
+}
+
+func (m FormSection) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	return &m, nil
+}
+
+func (m FormSection) View() string {
+	base := strings.Builder{}
+	base.WriteString(fmt.Sprintf("%s\n", m.theme.Color(m.title, Secondary)))
+	if m.description != "" {
+		base.WriteString(fmt.Sprintf("%s\n", m.theme.Color(m.description, Neutral)))
+	}
+	return base.String()
+}
+
+func (m *FormSection) Clear() {}
+
+func (m *FormSection) Focus() tea.Cmd {
+	return nil
+}
+
+func (m FormSection) Focused() bool {
+	return false
+}
+
+func (m *FormSection) Blur() {}
+
+func (m FormSection) Id() string {
+	return m.id
+}
+
+func (m FormSection) Value() any {
+	return nil
+}
@@ -0,0 +1,44 @@
+package boba
+
+import "fmt"
+
+// Reorders the ranked option indexes so that options in the same group are adjacent.
+// Groups are ordered by where they first appear in the options, after any leading groups,
+// so they stay in place while the user filters. The ranking is kept within each group
+func groupIndexes(indexes []int, matches [][]int, groups []string, leading ...string) ([]int, [][]int) {
+	order := make(map[string]int)
+	for _, list := range [][]string{leading, groups} {
+		for _, g := range list {
+			if _, ok := order[g]; !ok {
+				order[g] = len(order)
+			}
+		}
+	}
+	if len(order) < 2 {
+		return indexes, matches
+	}
+
+	buckets := make([][]int, len(order))
+	for i, idx := range indexes {
+		g := order[groups[idx]]
+		buckets[g] = append(buckets[g], i)
+	}
+
+	groupedIndexes := make([]int, 0, len(indexes))
+	groupedMatches := make([][]int, 0, len(matches))
+	for _, bucket := range buckets {
+		for _, i := range bucket {
+			groupedIndexes = append(groupedIndexes, indexes[i])
+			groupedMatches = append(groupedMatches, matches[i])
+		}
+	}
+	return groupedIndexes, groupedMatches
+}
+
+// Renders the header above a group of options, or the row of a collapsed group
+func renderGroupHeader(group string, collapsed bool, count int, theme Theme) string {
+	if collapsed {
+		return theme.Color(fmt.Sprintf("▸ %s", group), Secondary) + theme.Color(fmt.Sprintf(" (%d)", count), Neutral)
+	}
+	return theme.Color(fmt.Sprintf("▾ %s", group), Secondary)
+}
@@ -57,6 +57,22 @@ func (k keyMap) FullHelp() [][]key.Binding {
 			k.Up,
 			k.Down,
 			k.Filter,
+			k.PageUp,
+			k.PageDown,
+			k.Home,
+			k.End,
+			k.Collapse,
+			k.SelectAll,
+			k.SelectNone,
+			k.Invert,
+			k.SelectFiltered,
+			k.MoveUp,
+			k.MoveDown,
+			k.Pin,
+			k.Left,
+			k.Right,
+			k.Sort,
+			k.Reveal,
 		},
 	}
 }
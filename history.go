@@ -0,0 +1,165 @@
+package boba
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The groups that pinned and recently chosen options are placed in, ahead of the other
+// options. Options without a group of their own are placed in the last group
+const (
+	pinnedGroup = "Pinned"
+	recentGroup = "Recent"
+	otherGroup  = "Other"
+)
+
+// How many recently chosen options are offered first by default
+const defaultRecentLimit = 5
+
+// History records the options that are chosen in selectors, so that the options chosen
+// most frequently and recently can be offered first. It is persisted to a JSON file keyed
+// by the Name of each selector, so one history can be shared by several selectors
+type History struct {
+	path    string
+	entries map[string]*historyEntry
+	seq     int        // Incremented by every save so that older writes never replace newer ones
+	mu      sync.Mutex // Guards written, the saves run in parallel commands
+	written int        // The seq of the last save that reached the disk
+}
+
+// The options chosen in a single selector, along with its pinned options
+type historyEntry struct {
+	Uses   map[string]historyUse `json:"uses"`
+	Pinned []string              `json:"pinned"`
+}
+
+type historyUse struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// Creates a history that is stored at the provided path, reading any existing history.
+// A missing file is not an error
+func NewHistory(path string) (*History, error) {
+	h := &History{path: path, entries: make(map[string]*historyEntry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &h.entries); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Records that the option was chosen in the selector. Returns a command that saves the history
+func (h *History) record(name string, value string) tea.Cmd {
+	entry := h.entry(name)
+	use := entry.Uses[value]
+	entry.Uses[value] = historyUse{Count: use.Count + 1, Last: time.Now()}
+	return h.save()
+}
+
+// Pins the option in the selector, or unpins it if it is already pinned. Returns a
+// command that saves the history
+func (h *History) togglePin(name string, value string) tea.Cmd {
+	entry := h.entry(name)
+	if i := slices.Index(entry.Pinned, value); i != -1 {
+		entry.Pinned = slices.Delete(entry.Pinned, i, i+1)
+	} else {
+		entry.Pinned = append(entry.Pinned, value)
+	}
+	return h.save()
+}
+
+// Returns the groups that the options should be displayed in, by value, along with a
+// score for each chosen option that favors frequent and recent use. Pinned options are
+// placed in their own group, followed by the options chosen most recently up to the limit
+func (h *History) rank(name string, values []string, limit int) (map[string]string, map[string]int) {
+	groups := make(map[string]string)
+	scores := make(map[string]int)
+	entry, ok := h.entries[name]
+	if !ok {
+		return groups, scores
+	}
+
+	now := time.Now()
+	var recent []string
+	for _, value := range values {
+		if slices.Contains(entry.Pinned, value) {
+			groups[value] = pinnedGroup
+		}
+		use, ok := entry.Uses[value]
+		if !ok {
+			continue
+		}
+		scores[value] = use.score(now)
+		if groups[value] != pinnedGroup {
+			recent = append(recent, value)
+		}
+	}
+
+	sort.SliceStable(recent, func(a, b int) bool {
+		return scores[recent[a]] > scores[recent[b]]
+	})
+	for _, value := range recent[:min(limit, len(recent))] {
+		groups[value] = recentGroup
+	}
+	return groups, scores
+}
+
+// Scores how often the option has been chosen, where each use counts for half as much
+// after every week. A use is worth as much as a single character matched by the filter
+func (u historyUse) score(now time.Time) int {
+	weeks := now.Sub(u.Last).Hours() / (24 * 7)
+	return int(float64(u.Count*fuzzyMatchScore) * math.Pow(0.5, weeks))
+}
+
+// Returns the entry of the selector, creating it if it does not exist
+func (h *History) entry(name string) *historyEntry {
+	entry, ok := h.entries[name]
+	if !ok {
+		entry = &historyEntry{Uses: make(map[string]historyUse)}
+		h.entries[name] = entry
+	}
+	if entry.Uses == nil {
+		entry.Uses = make(map[string]historyUse)
+	}
+	return entry
+}
+
+// Returns a command that writes the history to disk. Saves run in parallel, so an older
+// snapshot is never written over a newer one
+func (h *History) save() tea.Cmd {
+	data, err := json.Marshal(h.entries)
+	if err != nil {
+		return func() tea.Msg { return ErrMsg{err} }
+	}
+
+	h.seq++
+	seq := h.seq
+
+	return func() tea.Msg {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if seq < h.written { // A newer save has already been written
+			return nil
+		}
+		if err := replaceFile(h.path, data); err != nil {
+			return ErrMsg{err}
+		}
+		h.written = seq
+		return nil
+	}
+}
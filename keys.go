@@ -5,26 +5,60 @@ import "github.com/charmbracelet/bubbles/key"
 // The KeyOpts struct contains all possible keys and can be used
 // to load in a set of keys for boba
 type KeyOpts struct {
-	Up     string `mapstructure:"up"`
-	Down   string `mapstructure:"down"`
-	Select string `mapstructure:"select"`
-	Toggle string `mapstructure:"toggle"`
-	Back   string `mapstructure:"back"`
-	Quit   string `mapstructure:"quit"`
-	Filter string `mapstructure:"filter"`
-	Help   string `mapstructure:"help"`
+	Up       string `mapstructure:"up"`
+	Down     string `mapstructure:"down"`
+	Select   string `mapstructure:"select"`
+	Toggle   string `mapstructure:"toggle"`
+	Back     string `mapstructure:"back"`
+	Quit     string `mapstructure:"quit"`
+	Filter   string `mapstructure:"filter"`
+	Help     string `mapstructure:"help"`
+	PageUp   string `mapstructure:"page_up"`
+	PageDown string `mapstructure:"page_down"`
+	Home     string `mapstructure:"home"`
+	End      string `mapstructure:"end"`
+	Collapse string `mapstructure:"collapse"`
+
+	SelectAll      string `mapstructure:"select_all"`
+	SelectNone     string `mapstructure:"select_none"`
+	Invert         string `mapstructure:"invert"`
+	SelectFiltered string `mapstructure:"select_filtered"`
+	MoveUp         string `mapstructure:"move_up"`
+	MoveDown       string `mapstructure:"move_down"`
+	Pin            string `mapstructure:"pin"`
+	Left           string `mapstructure:"left"`
+	Right          string `mapstructure:"right"`
+	Sort           string `mapstructure:"sort"`
+	Reveal         string `mapstructure:"reveal"`
 }
 
 // Contains a mapping of all keys to their key bindings (bubbletea type)
 type keyMap struct {
-	Quit   key.Binding
-	Back   key.Binding
-	Select key.Binding
-	Toggle key.Binding
-	Up     key.Binding
-	Down   key.Binding
-	Filter key.Binding
-	Help   key.Binding
+	Quit     key.Binding
+	Back     key.Binding
+	Select   key.Binding
+	Toggle   key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	Filter   key.Binding
+	Help     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Home     key.Binding
+	End      key.Binding
+	Collapse key.Binding
+
+	SelectAll      key.Binding
+	SelectNone     key.Binding
+	Invert         key.Binding
+	SelectFiltered key.Binding
+	MoveUp         key.Binding
+	MoveDown       key.Binding
+	Pin            key.Binding
+	Left           key.Binding
+	Right          key.Binding
+	Sort           key.Binding
+	Reveal         key.Binding
 }
 
 var bobaKeys KeyOpts
@@ -80,6 +114,86 @@ func newKeys(keys ...string) keyMap {
 				key.WithKeys(bobaKeys.Help),
 				key.WithHelp(bobaKeys.Help, "help"),
 			)
+		case bobaKeys.PageUp:
+			m.PageUp = key.NewBinding(
+				key.WithKeys(bobaKeys.PageUp),
+				key.WithHelp(bobaKeys.PageUp, "page up"),
+			)
+		case bobaKeys.PageDown:
+			m.PageDown = key.NewBinding(
+				key.WithKeys(bobaKeys.PageDown),
+				key.WithHelp(bobaKeys.PageDown, "page down"),
+			)
+		case bobaKeys.Home:
+			m.Home = key.NewBinding(
+				key.WithKeys(bobaKeys.Home),
+				key.WithHelp(bobaKeys.Home, "first"),
+			)
+		case bobaKeys.End:
+			m.End = key.NewBinding(
+				key.WithKeys(bobaKeys.End),
+				key.WithHelp(bobaKeys.End, "last"),
+			)
+		case bobaKeys.Collapse:
+			m.Collapse = key.NewBinding(
+				key.WithKeys(bobaKeys.Collapse),
+				key.WithHelp(bobaKeys.Collapse, "collapse/expand group"),
+			)
+		case bobaKeys.SelectAll:
+			m.SelectAll = key.NewBinding(
+				key.WithKeys(bobaKeys.SelectAll),
+				key.WithHelp(bobaKeys.SelectAll, "select all"),
+			)
+		case bobaKeys.SelectNone:
+			m.SelectNone = key.NewBinding(
+				key.WithKeys(bobaKeys.SelectNone),
+				key.WithHelp(bobaKeys.SelectNone, "select none"),
+			)
+		case bobaKeys.Invert:
+			m.Invert = key.NewBinding(
+				key.WithKeys(bobaKeys.Invert),
+				key.WithHelp(bobaKeys.Invert, "invert selection"),
+			)
+		case bobaKeys.SelectFiltered:
+			m.SelectFiltered = key.NewBinding(
+				key.WithKeys(bobaKeys.SelectFiltered),
+				key.WithHelp(bobaKeys.SelectFiltered, "select filtered"),
+			)
+		case bobaKeys.MoveUp:
+			m.MoveUp = key.NewBinding(
+				key.WithKeys(bobaKeys.MoveUp),
+				key.WithHelp(bobaKeys.MoveUp, "move up"),
+			)
+		case bobaKeys.MoveDown:
+			m.MoveDown = key.NewBinding(
+				key.WithKeys(bobaKeys.MoveDown),
+				key.WithHelp(bobaKeys.MoveDown, "move down"),
+			)
+		case bobaKeys.Pin:
+			m.Pin = key.NewBinding(
+				key.WithKeys(bobaKeys.Pin),
+				key.WithHelp(bobaKeys.Pin, "pin/unpin"),
+			)
+		case bobaKeys.Left:
+			m.Left = key.NewBinding(
+				key.WithKeys(bobaKeys.Left),
+				key.WithHelp(bobaKeys.Left, "previous column"),
+			)
+		case bobaKeys.Right:
+			m.Right = key.NewBinding(
+				key.WithKeys(bobaKeys.Right),
+				key.WithHelp(bobaKeys.Right, "next column"),
+			)
+		case bobaKeys.Sort:
+			m.Sort = key.NewBinding(
+				key.WithKeys(bobaKeys.Sort),
+				key.WithHelp(bobaKeys.Sort, "sort by column"),
+			)
+		case bobaKeys.Reveal:
+			m.Reveal = key.NewBinding(
+				key.WithKeys(bobaKeys.Reveal),
+				key.WithHelp(bobaKeys.Reveal, "show/hide password"),
+			)
 		}
 	}
 	return m
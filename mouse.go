@@ -0,0 +1,50 @@
+package boba
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// The area of the screen that a component was rendered to. Components handle mouse events
+// relative to the top left corner of their own view, so a model that renders a component
+// beneath other content should translate mouse events into the component's region before
+// passing them on. Mouse events must be enabled in the program, e.g. with tea.WithMouseCellMotion
+type Region struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// Indicates whether the point lies within the region
+func (r Region) Contains(x int, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Translates the mouse event into coordinates relative to the top left corner of the region
+func (r Region) Translate(msg tea.MouseMsg) tea.MouseMsg {
+	msg.X -= r.X
+	msg.Y -= r.Y
+	return msg
+}
+
+// Indicates whether the mouse event is a press of the left button
+func clicked(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// Returns the direction of a wheel event, or false for any other mouse event
+func scrolled(msg tea.MouseMsg) (Direction, bool) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return Up, true
+	case tea.MouseButtonWheelDown:
+		return Down, true
+	}
+	return "", false
+}
+
+// Components that handle mouse events implement the Regioner interface, which reports the
+// region their view takes up relative to its own top left corner. A parent that renders the
+// component at some position moves the region there, e.g. by setting its X and Y, and then
+// hit-tests and translates mouse events against it
+type Regioner interface {
+	Region() Region
+}
@@ -2,6 +2,7 @@ package boba
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -10,16 +11,22 @@ import (
 
 // An individual option in the MultiSelectorModel
 type MultiSelectorOption struct {
-	Label    string `json:"label"`
-	Value    string `json:"value"`
-	Selected bool   `json:"selected"`
-	Disabled bool   `json:"disabled"`
+	Label       string   `json:"label"`
+	Value       string   `json:"value"`
+	Selected    bool     `json:"selected"`
+	Disabled    bool     `json:"disabled"`
+	Description string   `json:"description"` // Secondary text, rendered dimmed next to the label
+	Keywords    []string `json:"keywords"`    // Additional text matched by the filter, but not displayed
+	Icon        string   `json:"icon"`        // Rendered before the label
+	Group       string   `json:"group"`       // Options are displayed beneath the header of their group
 }
 
 type MultiSelectorOptions []MultiSelectorOption
 
-// Message that can be used to set all of the options in the model
+// Message that can be used to set all of the options in the model. When a Name is provided
+// only the multi-selector with that name takes the options
 type MultiSelectorOptionsMsg struct {
+	Name    string
 	Options MultiSelectorOptions
 }
 
@@ -28,12 +35,29 @@ type MultiSelectorModel struct {
 	cursorIcon     string
 	options        MultiSelectorOptions
 	visibleOptions MultiSelectorOptions
+	visibleMatches [][]int // Positions of the runes matched by the filter in each visible option
+	visibleIndexes []int   // Index of each visible option in the options, or -1 for collapsed groups
 	filter         textinput.Model
+	filtering      optionFilter
 	keys           KeyOpts
 	theme          Theme
 	name           string
+	label          string
+	filterHidden   bool
 	maxHeight      func() int
-	truncated      bool
+	inactive       bool // Hides the cursor, used when the selector is an unfocused form field
+	offset         int  // Index of the first option in the scrolled window
+	collapsed      map[string]bool
+	groupCounts    map[string]int // Number of options in each group that match the filter
+	disabled       bool
+	validate       ValidateFunc
+	err            error
+	min            int
+	max            int
+	ordered        bool
+	order          []string // Values of the options in the order they were selected
+	creatable      bool
+	source         *sourceLoader[MultiSelectorOption]
 	LoadingModel
 }
 
@@ -42,8 +66,18 @@ type NewMultiSelectorModelOpts struct {
 	Options   []MultiSelectorOption
 	Theme     Theme
 	Name      string
+	Label     string
 	MaxHeight func() int
 	Keys      KeyOpts
+	Source    SourceOpts[MultiSelectorOption] // Loads the options on demand rather than via the MultiSelectorOptionsMsg
+	Disabled  bool
+	Validate  ValidateFunc // Run against the values of the selected options
+	Min       int          // The fewest options that can be submitted
+	Max       int          // The most options that can be selected, unlimited when zero
+	Ordered   bool         // Ranks the options by the order they are selected in, which can be changed
+	Creatable bool         // Offers to add the filter text as a new option when nothing matches it
+	NoUp      bool         // Keeps focus in the MultiSelectorField when moving up from the first option
+	NoDown    bool         // Keeps focus in the MultiSelectorField when moving down from the last option
 }
 
 // Allows for the toggling of multiple values in a list via a toggle mechanism.
@@ -53,9 +87,20 @@ func NewMultiSelectorModel(opts NewMultiSelectorModelOpts) MultiSelectorModel {
 		options:        opts.Options,
 		visibleOptions: opts.Options,
 		theme:          opts.Theme,
+		name:           opts.Name,
+		label:          opts.Label,
 		maxHeight:      opts.MaxHeight,
 		keys:           opts.Keys,
 		LoadingModel:   NewLoadingModel(),
+		filtering:      newOptionFilter(opts.Filter),
+		filterHidden:   opts.Filter.Hidden,
+		source:         newSourceLoader(opts.Source),
+		creatable:      opts.Creatable,
+		disabled:       opts.Disabled,
+		validate:       opts.Validate,
+		min:            opts.Min,
+		max:            opts.Max,
+		ordered:        opts.Ordered,
 	}
 
 	if !opts.Filter.Hidden {
@@ -64,6 +109,7 @@ func NewMultiSelectorModel(opts NewMultiSelectorModelOpts) MultiSelectorModel {
 		m.filter = ti
 	}
 
+	m.setOptions(opts.Options)
 	return m
 }
 
@@ -71,34 +117,103 @@ func NewMultiSelectorModel(opts NewMultiSelectorModelOpts) MultiSelectorModel {
 type unselectAllMsg struct{}
 
 func (m MultiSelectorModel) Init() tea.Cmd {
-	return func() tea.Msg {
+	unselectAll := func() tea.Msg {
 		return unselectAllMsg{}
 	}
+	if m.source != nil {
+		return tea.Batch(unselectAll, m.source.load())
+	}
+	return unselectAll
 }
 
 // Message used to set options in a MultiSelectorModel
 func (m MultiSelectorModel) Update(msg tea.Msg) (MultiSelectorModel, tea.Cmd) {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		if m.disabled {
+			return m, nil // Disabled selectors ignore input, but still take their options and results
+		}
+	}
+
 	var cmds []tea.Cmd
 
 	m.filter = UpdateSubmodel(m.filter, msg, &cmds)
 	m.Loader = m.UpdateLoading(msg, &cmds)
+	changed, cmd := m.filtering.receive(msg) // Long lists are filtered in the background once the debounce fires
+	if changed {
+		m.layout()
+	}
+	cmds = append(cmds, cmd)
+	if m.source != nil {
+		if options, ok := m.source.update(msg, m.options, &cmds); ok {
+			m.setOptions(options)
+		}
+	}
 
 	switch msg := msg.(type) {
 	case unselectAllMsg:
 		m.unselectAll()
 	case MultiSelectorOptionsMsg:
-		m.setOptions(msg.Options)
+		if msg.Name == "" || msg.Name == m.name {
+			m.setOptions(msg.Options)
+		}
 	case tea.KeyMsg:
 		switch msg.String() {
 		case m.keys.Down:
 			m.move(Down)
 		case m.keys.Up:
 			m.move(Up)
+		case m.keys.PageDown:
+			m.jump(m.pageSize())
+		case m.keys.PageUp:
+			m.jump(-m.pageSize())
+		case m.keys.Home:
+			m.jump(-len(m.visibleOptions))
+		case m.keys.End:
+			m.jump(len(m.visibleOptions))
+		case m.keys.Collapse:
+			if opt, ok := m.current(); ok && opt.Group != "" && !m.filter.Focused() {
+				m.toggleGroup(opt.Group)
+			}
 		case m.keys.Toggle:
-			cmds = append(cmds, m.toggleVal)
+			if opt, ok := m.current(); ok && m.isHeader(opt) && !m.filter.Focused() {
+				m.toggleGroup(opt.Group) // Toggling a collapsed group expands it
+				break
+			}
+			cmds = append(cmds, m.toggle())
+		case m.keys.SelectAll:
+			if !m.FilterFocused() {
+				cmds = append(cmds, m.selectBulk(m.allIndexes(), func(MultiSelectorOption) bool { return true }))
+			}
+		case m.keys.SelectNone:
+			if !m.FilterFocused() {
+				cmds = append(cmds, m.selectBulk(m.allIndexes(), func(MultiSelectorOption) bool { return false }))
+			}
+		case m.keys.Invert:
+			if !m.FilterFocused() {
+				cmds = append(cmds, m.selectBulk(m.allIndexes(), func(opt MultiSelectorOption) bool { return !opt.Selected }))
+			}
+		case m.keys.SelectFiltered:
+			if !m.FilterFocused() {
+				cmds = append(cmds, m.selectBulk(m.filtering.indexes, func(MultiSelectorOption) bool { return true }))
+			}
+		case m.keys.MoveUp:
+			cmds = append(cmds, m.reorder(Up))
+		case m.keys.MoveDown:
+			cmds = append(cmds, m.reorder(Down))
+		case m.keys.Select:
+			if m.canCreate() {
+				cmds = append(cmds, m.create())
+			} else if m.FilterFocused() {
+				m.filter.Blur()
+			} else if m.Validate() == nil {
+				cmds = append(cmds, m.submit)
+			}
 		case m.keys.Filter:
-			cmds = append(cmds, textinput.Blink)
-			m.filter.Focus()
+			if !m.filterHidden {
+				cmds = append(cmds, textinput.Blink)
+				m.filter.Focus()
+			}
 		case m.keys.Back:
 			if m.filter.Focused() {
 				m.filter.Blur()
@@ -107,13 +222,19 @@ func (m MultiSelectorModel) Update(msg tea.Msg) (MultiSelectorModel, tea.Cmd) {
 				return m, back(m.name)
 			}
 		}
+	case tea.MouseMsg:
+		cmds = append(cmds, m.mouse(msg))
 	}
 
-	if m.filter.Focused() {
-		m.cursor = 0
+	if m.err != nil { // Clear the error as soon as the user fixes the selection
+		m.Validate()
 	}
 
-	m.filterOptions()
+	cmds = append(cmds, m.filterOptions())
+	m.offset, _ = m.window()
+	if m.source != nil {
+		m.source.sync(m.filter.Value(), m.cursor, len(m.visibleOptions), &cmds)
+	}
 
 	return m, tea.Batch(cmds...)
 }
@@ -123,22 +244,48 @@ func (m MultiSelectorModel) View() string {
 		return fmt.Sprintf("\n%s\n", m.Loader.View())
 	}
 	base := strings.Builder{}
-	base.WriteString(rebuildCursor(m.filter.View(), m.filter.Focused(), m.theme))
-	if len(m.visibleOptions) == 0 {
+	if !m.filterHidden {
+		base.WriteString(rebuildCursor(m.filter.View(), m.filter.Focused(), m.theme))
+	}
+	if len(m.visibleOptions) == 0 && m.loadingOptions() {
+		base.WriteString(m.theme.Color("  Loading...\n", Neutral))
+	} else if m.canCreate() {
+		base.WriteString(renderCreate(m.createText(), m.theme))
+	} else if len(m.visibleOptions) == 0 {
 		base.WriteString("No options found \n")
 	} else {
-		for i, option := range m.visibleOptions {
+		ranks := m.ranks()
+		rankWidth := len(fmt.Sprint(len(ranks)))
+		start, end := m.window()
+		for i := start; i < end; i++ {
+			option := m.visibleOptions[i]
+			if m.hasHeader(i, start) {
+				base.WriteString(renderGroupHeader(option.Group, false, 0, m.theme) + "\n")
+			}
+
+			if m.isHeader(option) {
+				cursor := "  "
+				if i == m.cursor && !m.inactive {
+					cursor = m.theme.ColorCond("> ", Primary, !m.FilterFocused())
+				}
+				base.WriteString(cursor + renderGroupHeader(option.Group, true, m.groupCounts[option.Group], m.theme) + "\n")
+				continue
+			}
+
 			icon := "[x]"
-			if !option.Selected {
+			if !m.isSelected(i) {
 				icon = "[ ]"
 			}
+			if m.ordered {
+				icon += " " + rankView(ranks[option.Value], rankWidth)
+			}
 
 			var color ColorType
-			if option.Disabled {
+			if option.Disabled || m.disabled || (m.full() && !m.isSelected(i)) {
 				color = Neutral
 			}
 
-			if i == m.cursor {
+			if i == m.cursor && !m.inactive {
 				icon = m.theme.Color(icon, color)
 				base.WriteString(fmt.Sprintf("%s %s ", m.theme.ColorCond(">", Primary, !m.FilterFocused()), icon))
 			} else {
@@ -146,13 +293,22 @@ func (m MultiSelectorModel) View() string {
 				base.WriteString(fmt.Sprintf("%s  %s ", strings.Repeat(" ", len(m.cursorIcon)), icon))
 			}
 
-			base.WriteString(fmt.Sprintf("%s\n", m.theme.Color(option.Label, color)))
+			base.WriteString(fmt.Sprintf("%s\n", renderOption(option.Icon, option.Label, option.Description, matchesAt(m.visibleMatches, i), m.theme, color)))
 		}
 
-		if m.truncated {
-			base.WriteString(m.theme.Color(fmt.Sprintf("  Results limited, use %s to search...\n", m.keys.Filter), Neutral))
+		if start > 0 || end < len(m.visibleOptions) {
+			base.WriteString(scrollIndicator(start, end, len(m.visibleOptions), m.theme))
+		}
+		if m.loadingOptions() {
+			base.WriteString(m.theme.Color("  Loading...\n", Neutral))
 		}
 	}
+	if m.hasCounter() {
+		base.WriteString(m.counterView())
+	}
+	if m.err != nil {
+		base.WriteString(fmt.Sprintf("  %s\n", m.theme.Color(m.err.Error(), Error)))
+	}
 	return base.String()
 }
 
@@ -161,6 +317,201 @@ func (m MultiSelectorModel) FilterFocused() bool {
 	return m.filter.Focused()
 }
 
+// Moves the cursor back onto the option it was on before the visible options changed, or
+// keeps it within the bounds of the list when the option is no longer visible
+func (m *MultiSelectorModel) restoreCursor(prev MultiSelectorOption, hadPrev bool) {
+	if hadPrev {
+		i := findIndex(m.visibleOptions, func(opt MultiSelectorOption) bool {
+			if m.isHeader(prev) {
+				return m.isHeader(opt) && opt.Group == prev.Group
+			}
+			return !m.isHeader(opt) && opt.Value == prev.Value
+		})
+		if i != -1 {
+			m.cursor = i
+			return
+		}
+	}
+	m.cursor = min(max(m.cursor, 0), max(len(m.visibleOptions)-1, 0))
+}
+
+// Moves the cursor by several options at once, used for paging
+func (m *MultiSelectorModel) jump(delta int) {
+	if m.filter.Focused() {
+		return
+	}
+	m.cursor = moveCursor(m.cursor, delta, len(m.visibleOptions))
+}
+
+// Returns the number of lines available to the options
+func (m MultiSelectorModel) listHeight() int {
+	reserved := 0
+	if !m.filterHidden {
+		reserved++
+	}
+	if m.err != nil {
+		reserved++
+	}
+	if m.loadingOptions() {
+		reserved++
+	}
+	if m.hasCounter() {
+		reserved++
+	}
+	lines := 0
+	for i := range m.visibleOptions {
+		lines += m.optionLines(i, 0)
+		if m.maxHeight != nil && lines > m.maxHeight() {
+			break // The count makes no difference once the options overflow
+		}
+	}
+	return listHeight(m.maxHeight, reserved, lines)
+}
+
+// Indicates whether options are being loaded from the source or filtered in the background
+func (m MultiSelectorModel) loadingOptions() bool {
+	return (m.source != nil && m.source.loading) || m.filtering.pending
+}
+
+// Returns the bounds of the options that are displayed in the scrolled window
+func (m MultiSelectorModel) window() (int, int) {
+	return scrollWindow(m.offset, m.cursor, len(m.visibleOptions), m.listHeight(), m.optionLines)
+}
+
+// Returns the number of options that are displayed at once, used for paging
+func (m MultiSelectorModel) pageSize() int {
+	start, end := m.window()
+	return max(end-start, 1)
+}
+
+// Returns the number of lines the option takes up when the window begins at start
+func (m MultiSelectorModel) optionLines(i int, start int) int {
+	if m.hasHeader(i, start) {
+		return 2
+	}
+	return 1
+}
+
+// Indicates whether a group header is rendered above the option, which happens when a
+// new group begins and at the top of the window
+func (m MultiSelectorModel) hasHeader(i int, start int) bool {
+	opt := m.visibleOptions[i]
+	if opt.Group == "" || m.isHeader(opt) {
+		return false
+	}
+	return i == start || m.visibleOptions[i-1].Group != opt.Group
+}
+
+// Indicates whether the option is the row that stands in for a collapsed group
+func (m MultiSelectorModel) isHeader(opt MultiSelectorOption) bool {
+	return opt.Group != "" && m.collapsed[opt.Group]
+}
+
+// Collapses or expands the group, keeping the cursor on the group
+func (m *MultiSelectorModel) toggleGroup(group string) {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[group] = !m.collapsed[group]
+	m.layout()
+	m.cursor = max(findIndex(m.visibleOptions, func(opt MultiSelectorOption) bool {
+		return opt.Group == group
+	}), 0)
+}
+
+// Indicates whether the filter text can be added as a new option, which is offered once
+// nothing matches it
+func (m MultiSelectorModel) canCreate() bool {
+	return m.creatable && m.createText() != "" && len(m.visibleOptions) == 0 && !m.loadingOptions()
+}
+
+// The label and value of the option that would be created
+func (m MultiSelectorModel) createText() string {
+	return strings.TrimSpace(m.filter.Value())
+}
+
+// Adds the filter text as a new option, which is selected unless the maximum has been
+// reached. Triggers the MultiSelectorCreateMsg so that the new option can be saved
+func (m *MultiSelectorModel) create() tea.Cmd {
+	opt := MultiSelectorOption{Label: m.createText(), Value: m.createText()}
+	m.setOptions(append(slices.Clone(m.options), opt))
+	if !m.full() {
+		m.selectOption(len(m.options)-1, true)
+	}
+	m.clearFilter()
+	m.cursor = max(findIndex(m.visibleOptions, func(o MultiSelectorOption) bool { return o.Value == opt.Value }), 0)
+	msg := MultiSelectorCreateMsg{Name: m.name, Option: m.options[len(m.options)-1]}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Triggered when the filter text is added as a new option
+type MultiSelectorCreateMsg struct {
+	Name   string
+	Option MultiSelectorOption
+}
+
+// Empties and blurs the filter, showing all of the options again
+func (m *MultiSelectorModel) clearFilter() {
+	m.filter.SetValue("")
+	m.filter.Blur()
+	m.filterOptions() // Filtering by empty text never runs in the background
+}
+
+// Handles a mouse event relative to the top of the multi-selector. Clicking an option
+// toggles it and the wheel moves the cursor
+func (m *MultiSelectorModel) mouse(msg tea.MouseMsg) tea.Cmd {
+	if m.Loading {
+		return nil
+	}
+	if direction, ok := scrolled(msg); ok {
+		m.move(direction)
+		return nil
+	}
+	if !clicked(msg) {
+		return nil
+	}
+
+	line := msg.Y
+	if !m.filterHidden {
+		if line == 0 {
+			m.filter.Focus()
+			return textinput.Blink
+		}
+		line--
+	}
+	if m.canCreate() {
+		if line == 0 {
+			return m.create()
+		}
+		return nil
+	}
+
+	i := m.optionAt(line)
+	if i == -1 {
+		return nil
+	}
+	m.filter.Blur()
+	m.cursor = i
+	if opt := m.visibleOptions[i]; m.isHeader(opt) {
+		m.toggleGroup(opt.Group)
+		return nil
+	}
+	return m.toggle()
+}
+
+// Returns the region taken up by the view of the multi-selector
+func (m MultiSelectorModel) Region() Region {
+	return viewRegion(m.View(), 0, 0)
+}
+
+// Returns the index of the visible option rendered on the line of the list, or -1
+func (m MultiSelectorModel) optionAt(line int) int {
+	start, end := m.window()
+	return itemAt(line, start, end, m.optionLines)
+}
+
 // Moves the cursor up or down among the options
 func (m *MultiSelectorModel) move(direction Direction) {
 	if m.filter.Focused() {
@@ -185,35 +536,95 @@ func (m *MultiSelectorModel) unselectAll() {
 		results = append(results, opt)
 	}
 	m.options = results
+	m.order = nil
 }
 
-// Sets options on the selector
+// Sets options on the selector, keeping the selection of the options that remain
 func (m *MultiSelectorModel) setOptions(options []MultiSelectorOption) {
+	selected := make(map[string]bool, len(m.options))
+	for _, opt := range m.options {
+		selected[opt.Value] = opt.Selected
+	}
+	options = slices.Clone(options)
+	for i, opt := range options { // Options that were already present keep their selection
+		if s, ok := selected[opt.Value]; ok {
+			options[i].Selected = s
+		}
+	}
+
 	m.options = options
+	targets := make([]filterTarget, len(options))
+	groups := make([]string, len(options))
+	for i, opt := range options {
+		targets[i] = newFilterTarget(opt.Label, opt.Description, opt.Keywords)
+		groups[i] = opt.Group
+	}
+	m.filtering.setTargets(targets, groups)
 }
 
-// Filters the possible options by the text contained in the textinput model
-func (m *MultiSelectorModel) filterOptions() {
-	var visibleOptions MultiSelectorOptions
-	for _, opt := range m.options {
-		filter := m.filter.Value()
-		if filter == "" || strings.Contains(strings.ToLower(opt.Label), strings.ToLower(filter)) {
-			visibleOptions = append(visibleOptions, opt)
-		}
+// Filters the possible options by the text contained in the textinput model, ranking
+// them with the matcher. The options are only filtered again once the text or the options
+// change, returns a command when they are filtered in the background
+func (m *MultiSelectorModel) filterOptions() tea.Cmd {
+	query := m.filter.Value()
+	if m.source != nil {
+		query = "" // The source is responsible for filtering
 	}
 
-	// If we have exceeded the max height, trim our results
-	if m.maxHeight != nil {
-		h := m.maxHeight() - 2 // Include the height of the filter input
-		if len(visibleOptions) > h {
-			visibleOptions = visibleOptions[:h]
-			m.truncated = true
-		} else {
-			m.truncated = false
+	changed, cmd := m.filtering.update(query)
+	if changed {
+		m.layout()
+	}
+	return cmd
+}
+
+// Builds the visible options from the results of the filter, replacing collapsed groups
+// with a single header row. The cursor stays on the same option if it is still visible
+func (m *MultiSelectorModel) layout() {
+	prev, hadPrev := m.current()
+
+	var visibleOptions MultiSelectorOptions
+	var visibleMatches [][]int
+	var visibleIndexes []int
+	m.groupCounts = make(map[string]int)
+	for i, idx := range m.filtering.indexes {
+		opt := m.options[idx]
+		m.groupCounts[opt.Group]++
+		if m.collapsed[opt.Group] { // Collapsed groups are replaced by a single header row
+			if m.groupCounts[opt.Group] == 1 {
+				visibleOptions = append(visibleOptions, MultiSelectorOption{Label: opt.Group, Group: opt.Group})
+				visibleMatches = append(visibleMatches, nil)
+				visibleIndexes = append(visibleIndexes, -1)
+			}
+			continue
 		}
+		visibleOptions = append(visibleOptions, opt)
+		visibleMatches = append(visibleMatches, m.filtering.matches[i])
+		visibleIndexes = append(visibleIndexes, idx)
 	}
 
 	m.visibleOptions = visibleOptions
+	m.visibleMatches = visibleMatches
+	m.visibleIndexes = visibleIndexes
+	m.restoreCursor(prev, hadPrev)
+}
+
+// Returns the option under the cursor, if there is one
+func (m MultiSelectorModel) current() (MultiSelectorOption, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visibleOptions) {
+		return MultiSelectorOption{}, false
+	}
+	return m.visibleOptions[m.cursor], true
+}
+
+// Indicates whether the visible option at index i is selected. The selection is read from
+// the options, since toggling does not rebuild the visible options
+func (m MultiSelectorModel) isSelected(i int) bool {
+	if i >= len(m.visibleIndexes) {
+		return m.visibleOptions[i].Selected // The options have not been filtered yet
+	}
+	idx := m.visibleIndexes[i]
+	return idx >= 0 && idx < len(m.options) && m.options[idx].Selected
 }
 
 // Message for a single toggle event
@@ -221,19 +632,262 @@ type MultiSelectorOptionMsg struct {
 	Option MultiSelectorOption
 }
 
-// Toggles the current value. Triggers the MultiSelectorOptionMsg which can be handled
-// by other components
-func (m *MultiSelectorModel) toggleVal() tea.Msg {
-	if !m.FilterFocused() {
-		val := m.visibleOptions[m.cursor].Value
-		i := findIndex(m.options, func(opt MultiSelectorOption) bool {
-			return opt.Value == val
-		})
-		if m.options[i].Disabled {
-			return nil
+// Toggles the option under the cursor. Triggers the MultiSelectorOptionMsg which can be
+// handled by other components
+func (m *MultiSelectorModel) toggle() tea.Cmd {
+	opt, ok := m.current()
+	if !ok || m.FilterFocused() || m.isHeader(opt) {
+		return nil
+	}
+	i := findIndex(m.options, func(o MultiSelectorOption) bool {
+		return o.Value == opt.Value
+	})
+	if i == -1 || m.options[i].Disabled || (m.full() && !m.options[i].Selected) {
+		return nil
+	}
+	m.selectOption(i, !m.options[i].Selected)
+	option := m.options[i]
+	return func() tea.Msg {
+		return MultiSelectorOptionMsg{option}
+	}
+}
+
+// Sets the selection of the option at index i, recording the order of the selection
+func (m *MultiSelectorModel) selectOption(i int, selected bool) {
+	value := m.options[i].Value
+	m.options[i].Selected = selected
+	m.order = slices.DeleteFunc(m.order, func(v string) bool { return v == value })
+	if selected {
+		m.order = append(m.order, value)
+	}
+}
+
+// Returns the selected options, in the order they were selected when the multi-selector is
+// ordered and in the order of the options otherwise
+func (m MultiSelectorModel) Selection() []MultiSelectorOption {
+	var selection []MultiSelectorOption
+	if !m.ordered {
+		for _, opt := range m.options {
+			if opt.Selected {
+				selection = append(selection, opt)
+			}
+		}
+		return selection
+	}
+
+	indexes := make(map[string]int, len(m.options))
+	for i, opt := range m.options {
+		indexes[opt.Value] = i
+	}
+	ranked := make(map[string]bool, len(m.order))
+	for _, v := range m.order {
+		if i, ok := indexes[v]; ok && m.options[i].Selected && !ranked[v] {
+			selection = append(selection, m.options[i])
+			ranked[v] = true
+		}
+	}
+	for _, opt := range m.options { // Options that were selected when they were set come last
+		if opt.Selected && !ranked[opt.Value] {
+			selection = append(selection, opt)
 		}
-		m.options[i].Selected = !m.options[i].Selected
-		return MultiSelectorOptionMsg{m.options[i]}
 	}
+	return selection
+}
+
+// Returns the rank of each selected option by its value, starting at 1
+func (m MultiSelectorModel) ranks() map[string]int {
+	ranks := make(map[string]int)
+	for i, opt := range m.Selection() {
+		ranks[opt.Value] = i + 1
+	}
+	return ranks
+}
+
+// Renders the rank of a selected option, padded so that the labels line up
+func rankView(rank int, width int) string {
+	if rank == 0 {
+		return strings.Repeat(" ", width+1)
+	}
+	return fmt.Sprintf("%*d.", width, rank)
+}
+
+// Message for a change in the order of the selected options of an ordered multi-selector
+type MultiSelectorReorderMsg struct {
+	Name    string
+	Options []MultiSelectorOption
+}
+
+// Moves the selected option under the cursor up or down in the ranking. Triggers the
+// MultiSelectorReorderMsg with the new order of the selection
+func (m *MultiSelectorModel) reorder(direction Direction) tea.Cmd {
+	opt, ok := m.current()
+	if !ok || !m.ordered || m.FilterFocused() || !m.isSelected(m.cursor) {
+		return nil
+	}
+
+	sel := m.Selection() // Drops values from the ranking that are no longer selected
+	m.order = nil
+	for _, selected := range sel {
+		m.order = append(m.order, selected.Value)
+	}
+
+	i := slices.Index(m.order, opt.Value)
+	j := i - 1
+	if direction == Down {
+		j = i + 1
+	}
+	if j < 0 || j >= len(m.order) {
+		return nil
+	}
+	m.order[i], m.order[j] = m.order[j], m.order[i]
+
+	msg := MultiSelectorReorderMsg{Name: m.name, Options: m.Selection()}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Triggered by the select key once the selection meets the minimum, contains the selected options
+type MultiSelectorSubmitMsg struct {
+	Name    string
+	Options []MultiSelectorOption
+}
+
+func (m *MultiSelectorModel) submit() tea.Msg {
+	return MultiSelectorSubmitMsg{Name: m.name, Options: m.Selection()}
+}
+
+// Returns the number of selected options
+func (m MultiSelectorModel) count() int {
+	n := 0
+	for _, opt := range m.options {
+		if opt.Selected {
+			n++
+		}
+	}
+	return n
+}
+
+// Indicates whether the maximum number of options are selected
+func (m MultiSelectorModel) full() bool {
+	return m.max > 0 && m.count() >= m.max
+}
+
+// The counter is only shown when the selection is constrained
+func (m MultiSelectorModel) hasCounter() bool {
+	return m.min > 0 || m.max > 0
+}
+
+// Renders the number of selected options along with the constraints, e.g. "2/3 selected"
+func (m MultiSelectorModel) counterView() string {
+	counter := fmt.Sprintf("%d selected", m.count())
+	if m.max > 0 {
+		counter = fmt.Sprintf("%d/%d selected", m.count(), m.max)
+	}
+	if m.min > 0 {
+		counter += fmt.Sprintf(", at least %d", m.min)
+	}
+	return m.theme.Color("  "+counter+"\n", Neutral)
+}
+
+// Message for the bulk actions, such as selecting all of the options. Contains every
+// option whose selection changed
+type MultiSelectorBulkMsg struct {
+	Name    string
+	Options []MultiSelectorOption
+}
+
+// Sets the selection of the options at the indexes, skipping disabled options and those
+// that would exceed the maximum. Triggers a single MultiSelectorBulkMsg listing the
+// options that changed
+func (m *MultiSelectorModel) selectBulk(indexes []int, selected func(opt MultiSelectorOption) bool) tea.Cmd {
+	var changed []MultiSelectorOption
+	for _, i := range indexes {
+		opt := m.options[i]
+		if opt.Disabled || selected(opt) == opt.Selected {
+			continue
+		}
+		if !opt.Selected && m.full() {
+			continue // Options are still unselected, e.g. when inverting
+		}
+		m.selectOption(i, !opt.Selected)
+		changed = append(changed, m.options[i])
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	msg := MultiSelectorBulkMsg{Name: m.name, Options: changed}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Returns the indexes of all of the options, including those hidden by the filter
+func (m MultiSelectorModel) allIndexes() []int {
+	indexes := make([]int, len(m.options))
+	for i := range m.options {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+// Returns the values of the selected options, in the order of the selection when ordered
+func (m MultiSelectorModel) Value() any {
+	var values []string
+	for _, opt := range m.Selection() {
+		values = append(values, opt.Value)
+	}
+	return values
+}
+
+// Selects exactly the options with the provided values, the value must be a slice of strings
+func (m *MultiSelectorModel) SetValue(value any) error {
+	var values []string
+	switch v := value.(type) {
+	case []string:
+		values = v
+	case []any: // Values decoded from JSON
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("multi-selector %q expects strings, got %T", m.name, item)
+			}
+			values = append(values, s)
+		}
+	case nil:
+	default:
+		return fmt.Errorf("multi-selector %q expects a slice of strings, got %T", m.name, value)
+	}
+
+	for i, opt := range m.options {
+		m.options[i].Selected = slices.Contains(values, opt.Value)
+	}
+	m.order = slices.Clone(values)
 	return nil
 }
+
+func (m MultiSelectorModel) Label() string {
+	return m.label
+}
+
+func (m MultiSelectorModel) Disabled() bool {
+	return m.disabled
+}
+
+func (m *MultiSelectorModel) SetDisabled(disabled bool) {
+	m.disabled = disabled
+}
+
+func (m *MultiSelectorModel) Validate() error {
+	m.err = nil
+	if m.count() < m.min {
+		m.err = fmt.Errorf("select at least %d option(s)", m.min)
+	} else if m.validate != nil {
+		m.err = m.validate(m.Value())
+	}
+	return m.err
+}
+
+func (m MultiSelectorModel) Err() error {
+	return m.err
+}
@@ -0,0 +1,75 @@
+package boba
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func press(m MultiSelectorModel, key string) (MultiSelectorModel, tea.Msg) {
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	if cmd == nil {
+		return m, nil
+	}
+	return m, cmd()
+}
+
+func rankedValues(m MultiSelectorModel) []string {
+	var values []string
+	for _, opt := range m.Selection() {
+		values = append(values, opt.Value)
+	}
+	return values
+}
+
+func TestMultiSelectorReorder(t *testing.T) {
+	m := NewMultiSelectorModel(NewMultiSelectorModelOpts{
+		Ordered: true,
+		Filter:  FilterOpts{Hidden: true},
+		Keys:    KeyOpts{Up: "k", Down: "j", Toggle: "x", MoveUp: "K", MoveDown: "J"},
+		Options: MultiSelectorOptions{
+			{Label: "A", Value: "a"},
+			{Label: "B", Value: "b"},
+			{Label: "C", Value: "c"},
+		},
+	})
+
+	// Select c, then a, then b, which ranks them in that order
+	m, _ = press(m, "j")
+	m, _ = press(m, "j")
+	m, _ = press(m, "x")
+	m, _ = press(m, "k")
+	m, _ = press(m, "k")
+	m, _ = press(m, "x")
+	m, _ = press(m, "j")
+	m, _ = press(m, "x")
+	if got := rankedValues(m); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Fatalf("expected the selection to be ranked [c a b], got %v", got)
+	}
+
+	// Move a, which is ranked second, above c
+	m, _ = press(m, "k")
+	m, msg := press(m, "K")
+	if got := rankedValues(m); !slices.Equal(got, []string{"a", "c", "b"}) {
+		t.Fatalf("expected the selection to be ranked [a c b], got %v", got)
+	}
+	reorder, ok := msg.(MultiSelectorReorderMsg)
+	if !ok {
+		t.Fatalf("expected a MultiSelectorReorderMsg, got %T", msg)
+	}
+	if len(reorder.Options) != 3 || reorder.Options[0].Value != "a" {
+		t.Fatalf("expected the message to contain the new ranking, got %v", reorder.Options)
+	}
+
+	// The first option cannot move further up
+	if m, msg = press(m, "K"); msg != nil {
+		t.Fatalf("expected no message when moving the first option up, got %T", msg)
+	}
+
+	// Move a back down below c
+	m, _ = press(m, "J")
+	if got := rankedValues(m); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Fatalf("expected the selection to be ranked [c a b], got %v", got)
+	}
+}
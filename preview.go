@@ -0,0 +1,219 @@
+package boba
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Where the preview is rendered relative to the options
+type PreviewPosition string
+
+const (
+	PreviewRight  PreviewPosition = "right"
+	PreviewBottom PreviewPosition = "bottom"
+)
+
+// Configures the pane that shows details about the option under the cursor, similar to
+// the preview window of fzf
+type PreviewOpts struct {
+	Preview  func(ctx context.Context, option SelectorOption) (string, error)
+	Async    bool            // Runs the preview in a command, the context is cancelled once the cursor moves
+	Position PreviewPosition // Defaults to PreviewRight
+	Width    func() int      // The width that is split between the options and a preview on the right
+	Size     int             // The percentage of the width taken by a preview on the right, or the lines taken by a preview at the bottom. Defaults to 50% and 10 lines
+}
+
+const (
+	defaultPreviewPercent = 50
+	defaultPreviewLines   = 10
+)
+
+// Used to tell apart the previews of different selectors in the same program
+var previewIds atomic.Int64
+
+// Contains the result of an async preview
+type previewMsg struct {
+	id      int64
+	seq     int
+	content string
+	err     error
+}
+
+// Tracks the preview of the option under the cursor. Every time the cursor moves to another
+// option the sequence number is incremented, so that stale previews can be dropped
+type previewPane struct {
+	id       int64
+	preview  func(ctx context.Context, option SelectorOption) (string, error)
+	async    bool
+	position PreviewPosition
+	width    func() int
+	size     int
+	value    string // The value of the option being previewed
+	shown    bool   // Whether there is an option to preview
+	seq      int
+	content  string
+	err      error
+	loading  bool
+	cancel   context.CancelFunc
+}
+
+func newPreviewPane(opts PreviewOpts) *previewPane {
+	if opts.Preview == nil {
+		return nil
+	}
+	if opts.Position == "" {
+		opts.Position = PreviewRight
+	}
+	if opts.Size == 0 && opts.Position == PreviewRight {
+		opts.Size = defaultPreviewPercent
+	}
+	if opts.Size == 0 {
+		opts.Size = defaultPreviewLines
+	}
+	return &previewPane{
+		id:       previewIds.Add(1),
+		preview:  opts.Preview,
+		async:    opts.Async,
+		position: opts.Position,
+		width:    opts.Width,
+		size:     opts.Size,
+	}
+}
+
+// Previews the option under the cursor if it has changed. Returns a command when the
+// preview runs asynchronously, cancelling the preview of the previous option
+func (p *previewPane) update(option SelectorOption, ok bool) tea.Cmd {
+	if ok == p.shown && option.Value == p.value {
+		return nil
+	}
+	p.shown, p.value = ok, option.Value
+	p.seq++
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+
+	p.content, p.err, p.loading = "", nil, false
+	if !ok {
+		return nil
+	}
+	if !p.async {
+		p.content, p.err = p.preview(context.Background(), option)
+		return nil
+	}
+
+	p.loading = true
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	id, seq, preview := p.id, p.seq, p.preview
+	return func() tea.Msg {
+		content, err := preview(ctx, option)
+		return previewMsg{id: id, seq: seq, content: content, err: err}
+	}
+}
+
+// Stores the result of an async preview, unless the cursor has moved since it started
+func (p *previewPane) receive(msg tea.Msg) {
+	result, ok := msg.(previewMsg)
+	if !ok || result.id != p.id || result.seq != p.seq {
+		return
+	}
+	p.loading = false
+	p.content = result.content
+	if !errors.Is(result.err, context.Canceled) {
+		p.err = result.err
+	}
+}
+
+// Returns the lines of the preview
+func (p *previewPane) lines(theme Theme) []string {
+	switch {
+	case p.loading:
+		return []string{theme.Color("Loading...", Neutral)}
+	case p.err != nil:
+		return []string{theme.Color(p.err.Error(), Error)}
+	case p.content == "":
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(p.content, "\n"), "\n")
+}
+
+// Returns the lines that a preview at the bottom takes from the list, including its separator
+func (p *previewPane) reserved() int {
+	if p.position != PreviewBottom {
+		return 0
+	}
+	return p.size + 1
+}
+
+// Renders the preview next to or below the list of options. A preview on the right is as
+// tall as the list, or as the preview itself up to the max height when one is provided. A
+// preview at the bottom is cut off at the max height
+func (p *previewPane) view(list string, maxHeight int, theme Theme) string {
+	listLines := strings.Split(strings.TrimSuffix(list, "\n"), "\n")
+	previewLines := p.lines(theme)
+
+	listWidth := 0
+	for _, line := range listLines {
+		listWidth = max(listWidth, lipgloss.Width(line))
+	}
+
+	base := strings.Builder{}
+	if p.position == PreviewBottom {
+		width := listWidth
+		if p.width != nil {
+			width = p.width()
+		}
+		size := p.size
+		if maxHeight > 0 {
+			size = min(size, maxHeight-len(listLines)-1)
+		}
+		base.WriteString(list)
+		if size < 0 {
+			return base.String() // No room for the separator
+		}
+		base.WriteString(theme.Color(strings.Repeat("─", max(width, 1)), Neutral) + "\n")
+		for _, line := range previewLines[:min(len(previewLines), size)] {
+			base.WriteString(truncate(line, width) + "\n")
+		}
+		return base.String()
+	}
+
+	previewWidth := 0 // Unlimited when the available width is unknown
+	if p.width != nil {
+		listWidth = p.width() * (100 - p.size) / 100
+		previewWidth = max(p.width()-listWidth-3, 1) // Room for the separator
+	}
+
+	height := len(previewLines)
+	if maxHeight > 0 {
+		height = min(height, maxHeight)
+	}
+	height = max(height, len(listLines))
+
+	for i := 0; i < height; i++ {
+		var left, right string
+		if i < len(listLines) {
+			left = truncate(listLines[i], listWidth)
+		}
+		if i < len(previewLines) {
+			right = truncate(previewLines[i], previewWidth)
+		}
+		left += strings.Repeat(" ", max(listWidth-lipgloss.Width(left), 0))
+		base.WriteString(left + theme.Color(" │ ", Neutral) + right + "\n")
+	}
+	return base.String()
+}
+
+// Cuts the line off at the width, which is unlimited when zero
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return line
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
@@ -28,26 +28,48 @@ type Views []View
 var viewStack []string
 
 type Router struct {
-	Model       tea.Model
-	Views       Views
-	DefaultView string // View that is navigated to when "back" is called w/out a previous route
-	QuitKey     string
+	Model          tea.Model
+	Views          Views
+	DefaultView    string // View that is navigated to when "back" is called w/out a previous route
+	QuitKey        string
+	ConfirmDiscard bool    // Prompt before leaving a model with unsaved changes
+	pending        tea.Msg // Navigation awaiting confirmation from the user
 }
 
 type NewRouterModelOpts struct {
-	View        string
-	Views       Views
-	Quit        string
-	DefaultView string
+	View           string
+	Views          Views
+	Quit           string
+	DefaultView    string
+	ConfirmDiscard bool
 }
 
+// Models that implement the DirtyModel interface are guarded by the router when
+// ConfirmDiscard is set: navigating away from them or quitting while they are dirty
+// will prompt the user before discarding their changes. The check runs when the navigation
+// message arrives, so a model that saves its changes and then navigates, e.g. after a form
+// is submitted, should reset itself before returning the Push, Replace or Pop command
+type DirtyModel interface {
+	Dirty() bool
+}
+
+// Keys used to answer the discard prompt
+const (
+	confirmKey = "y"
+	cancelKey  = "n"
+)
+
+// Used to defer a quit until the user confirms the discard prompt
+type quitMsg struct{}
+
 // The Router is responsible for changing the top-level model in the application and triggering any route-based updates
 // Creates a new router that is responsible for handling navigation around the application via the changeView function
 func NewRouterModel(opts NewRouterModelOpts) tea.Model {
 	r := Router{
-		Views:       opts.Views,
-		DefaultView: opts.DefaultView,
-		QuitKey:     opts.Quit,
+		Views:          opts.Views,
+		DefaultView:    opts.DefaultView,
+		QuitKey:        opts.Quit,
+		ConfirmDiscard: opts.ConfirmDiscard,
 	}
 
 	r.pushModel(opts.View)
@@ -55,28 +77,77 @@ func NewRouterModel(opts NewRouterModelOpts) tea.Model {
 }
 
 func (m Router) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.pending != nil { // While the discard prompt is shown it receives all keypresses
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			return m.answerPrompt(msg)
+		case tea.MouseMsg:
+			return m, nil // The model beneath the prompt is not clickable
+		}
+	}
+
 	if cmd := m.handleQuit(msg); cmd != nil { // Our global quit handler shortcuts the event loop
+		if m.guarded() {
+			m.pending = quitMsg{}
+			return m, nil
+		}
 		return m, cmd
 	}
 
 	// When a component triggers a view change we set the new model
 	// and then set router params. This RouterParamsMsg can be detected by components
 	// that need query parameters, or other data
+	switch msg.(type) {
+	case pushViewMsg, replaceViewMsg, popMsg:
+		if m.guarded() {
+			m.pending = msg
+			return m, nil
+		}
+		return m.navigate(msg)
+	}
+
+	var cmd tea.Cmd
+	m.Model, cmd = m.Model.Update(msg) // Delegate updates to the model
+	return m, cmd
+}
+
+// Changes the current model based on the navigation message
+func (m Router) navigate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case pushViewMsg:
 		m.pushModel(msg.view)
-		return m, m.Model.Init()
 	case replaceViewMsg:
 		m.replaceModel(msg.view)
-		return m, m.Model.Init()
 	case popMsg:
 		m.popModel()
-		return m, m.Model.Init()
+	case quitMsg:
+		return m, tea.Quit
 	}
+	return m, m.Model.Init()
+}
 
-	var cmd tea.Cmd
-	m.Model, cmd = m.Model.Update(msg) // Delegate updates to the model
-	return m, cmd
+// Indicates whether leaving the current model requires confirmation
+func (m Router) guarded() bool {
+	if !m.ConfirmDiscard {
+		return false
+	}
+	d, ok := m.Model.(DirtyModel)
+	return ok && d.Dirty()
+}
+
+// Completes or cancels the pending navigation based on the user's answer
+func (m Router) answerPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	pending := m.pending
+	switch msg.String() {
+	case m.QuitKey: // Quitting from the prompt does not ask again
+		return m, tea.Quit
+	case confirmKey:
+		m.pending = nil
+		return m.navigate(pending)
+	case cancelKey, "esc":
+		m.pending = nil
+	}
+	return m, nil
 }
 
 func (m Router) View() string {
@@ -86,6 +157,10 @@ func (m Router) View() string {
 	base += lipgloss.NewStyle(). // Helper text to show the current route
 					Foreground(lipgloss.Color("#616161")).
 					Render(fmt.Sprintf("\nPath: %s", currentView))
+
+	if m.pending != nil {
+		base += fmt.Sprintf("\nDiscard unsaved changes? (%s/%s)", confirmKey, cancelKey)
+	}
 	return base
 }
 
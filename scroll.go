@@ -0,0 +1,78 @@
+package boba
+
+import "fmt"
+
+// Returns the bounds [start, end) of the window over a list that fits within the height
+// while keeping the cursor visible, moving the window as little as possible from its
+// previous offset. The lines function returns the number of lines taken up by item i
+// when the window begins at start
+func scrollWindow(offset int, cursor int, total int, height int, lines func(i int, start int) int) (int, int) {
+	if total == 0 {
+		return 0, 0
+	}
+	height = max(height, 1)
+
+	end := func(start int) int {
+		used, i := 0, start
+		for ; i < total; i++ {
+			used += lines(i, start)
+			if used > height {
+				break
+			}
+		}
+		return max(i, start+1) // Always show at least one item
+	}
+
+	cursor = min(max(cursor, 0), total-1)
+	start := min(max(offset, 0), cursor)
+	for start < cursor && end(start) <= cursor {
+		start++
+	}
+	for start > 0 && end(start-1) == total { // Fill the window once the end of the list is reached
+		start--
+	}
+	return start, end(start)
+}
+
+// Returns the number of lines available to the items of a list within the max height,
+// after making room for the other lines of the component and for the scroll indicator
+func listHeight(maxHeight func() int, reserved int, totalLines int) int {
+	if maxHeight == nil {
+		return totalLines
+	}
+	h := maxHeight() - reserved
+	if totalLines > h {
+		h-- // Room for the scroll indicator
+	}
+	return max(h, 1)
+}
+
+// Renders the position of the window within the list, e.g. "11-20 of 52"
+func scrollIndicator(start int, end int, total int, theme Theme) string {
+	return theme.Color(fmt.Sprintf("  %d-%d of %d\n", start+1, end, total), Neutral)
+}
+
+// Moves the cursor by delta, keeping it within the bounds of the list
+func moveCursor(cursor int, delta int, total int) int {
+	return min(max(cursor+delta, 0), max(total-1, 0))
+}
+
+// Returns the item rendered on the line of a window that begins at start, or -1 when the
+// line lies outside of the window or holds a group header. An item is rendered on the last
+// of the lines it takes up, beneath its header
+func itemAt(line int, start int, end int, lines func(i int, start int) int) int {
+	if line < 0 {
+		return -1
+	}
+	for i := start; i < end; i++ {
+		n := lines(i, start)
+		if line < n {
+			if line == n-1 {
+				return i
+			}
+			return -1
+		}
+		line -= n
+	}
+	return -1
+}
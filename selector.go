@@ -2,6 +2,7 @@ package boba
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -9,9 +10,13 @@ import (
 )
 
 type SelectorOption struct {
-	Label    string `json:"label"`
-	Value    string `json:"value"`
-	Disabled bool   `json:"disabled"`
+	Label       string   `json:"label"`
+	Value       string   `json:"value"`
+	Disabled    bool     `json:"disabled"`
+	Description string   `json:"description"` // Secondary text, rendered dimmed next to the label
+	Keywords    []string `json:"keywords"`    // Additional text matched by the filter, but not displayed
+	Icon        string   `json:"icon"`        // Rendered before the label
+	Group       string   `json:"group"`       // Options are displayed beneath the header of their group
 }
 
 type SelectorOptions []SelectorOption
@@ -22,13 +27,29 @@ type SelectorModel struct {
 	cursorIcon     string
 	options        SelectorOptions
 	visibleOptions SelectorOptions
+	visibleMatches [][]int // Positions of the runes matched by the filter in each visible option
 	filter         textinput.Model
+	filtering      optionFilter
 	theme          Theme
 	name           string
+	label          string
+	selected       string // Value of the last selected option
 	filterHidden   bool
+	inactive       bool // Hides the cursor, used when the selector is an unfocused form field
+	showSelected   bool // Colors the selected option, used when the selector is a form field
 	maxHeight      func() int
-	truncated      bool
+	offset         int // Index of the first option in the scrolled window
+	collapsed      map[string]bool
+	groupCounts    map[string]int // Number of options in each group that match the filter
+	disabled       bool
+	validate       ValidateFunc
+	err            error
 	keys           KeyOpts
+	creatable      bool
+	history        *History
+	recent         int
+	preview        *previewPane
+	source         *sourceLoader[SelectorOption]
 	LoadingModel
 }
 
@@ -37,8 +58,18 @@ type NewSelectorModelOpts struct {
 	Options   []SelectorOption
 	Theme     Theme
 	Name      string
+	Label     string
 	MaxHeight func() int
 	Keys      KeyOpts
+	Source    SourceOpts[SelectorOption] // Loads the options on demand rather than via the SelectorOptionsMsg
+	Disabled  bool
+	Validate  ValidateFunc // Run against the value of the selected option
+	Creatable bool         // Offers to add the filter text as a new option when nothing matches it
+	History   *History     // Records the chosen options, which are then offered first
+	Recent    int          // How many of the recently chosen options are offered first, defaults to 5
+	Preview   PreviewOpts  // Shows details about the option under the cursor
+	NoUp      bool         // Keeps focus in the SelectorField when moving up from the first option
+	NoDown    bool         // Keeps focus in the SelectorField when moving down from the last option
 }
 
 // Allows for the selection of a single value among a list of options.
@@ -48,10 +79,20 @@ func NewSelectorModel(opts NewSelectorModelOpts) SelectorModel {
 		options:        opts.Options,
 		visibleOptions: opts.Options,
 		theme:          opts.Theme,
+		name:           opts.Name,
+		label:          opts.Label,
 		maxHeight:      opts.MaxHeight,
 		keys:           opts.Keys,
 		LoadingModel:   NewLoadingModel(),
+		filtering:      newOptionFilter(opts.Filter),
 		filterHidden:   opts.Filter.Hidden,
+		source:         newSourceLoader(opts.Source),
+		creatable:      opts.Creatable,
+		history:        opts.History,
+		recent:         opts.Recent,
+		preview:        newPreviewPane(opts.Preview),
+		disabled:       opts.Disabled,
+		validate:       opts.Validate,
 	}
 
 	if !opts.Filter.Hidden {
@@ -60,39 +101,104 @@ func NewSelectorModel(opts NewSelectorModelOpts) SelectorModel {
 		m.filter = ti
 	}
 
+	if m.recent == 0 {
+		m.recent = defaultRecentLimit
+	}
+	if m.history != nil { // The pinned and recent options are placed at the top
+		m.filtering.leading = []string{pinnedGroup, recentGroup}
+	}
+
+	m.setOptions(opts.Options)
 	return m
 }
 
 func (m SelectorModel) Init() tea.Cmd {
+	if m.source != nil {
+		return m.source.load()
+	}
 	return nil
 }
 
-// Used to set the options in the model
+// Used to set the options in the model. When a Name is provided only the selector with
+// that name takes the options, otherwise every selector that receives the message does
 type SelectorOptionsMsg struct {
+	Name    string
 	Options SelectorOptions
 }
 
 func (m SelectorModel) Update(msg tea.Msg) (SelectorModel, tea.Cmd) {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		if m.disabled {
+			return m, nil // Disabled selectors ignore input, but still take their options and results
+		}
+	}
+
 	var cmds []tea.Cmd
 
 	m.filter = UpdateSubmodel(m.filter, msg, &cmds)
 	m.Loader = m.UpdateLoading(msg, &cmds)
+	changed, cmd := m.filtering.receive(msg) // Long lists are filtered in the background once the debounce fires
+	if changed {
+		m.layout()
+	}
+	cmds = append(cmds, cmd)
+	if m.preview != nil {
+		m.preview.receive(msg)
+	}
+	if m.source != nil {
+		if options, ok := m.source.update(msg, m.options, &cmds); ok {
+			m.setOptions(options)
+		}
+	}
 
 	switch msg := msg.(type) {
 	case SelectorOptionsMsg:
-		m.setOptions(msg.Options)
+		if msg.Name == "" || msg.Name == m.name {
+			m.setOptions(msg.Options)
+		}
 	case tea.KeyMsg:
 		switch msg.String() {
 		case m.keys.Down:
 			m.move(Down)
 		case m.keys.Up:
 			m.move(Up)
+		case m.keys.PageDown:
+			m.jump(m.pageSize())
+		case m.keys.PageUp:
+			m.jump(-m.pageSize())
+		case m.keys.Home:
+			m.jump(-len(m.visibleOptions))
+		case m.keys.End:
+			m.jump(len(m.visibleOptions))
+		case m.keys.Collapse:
+			if opt, ok := m.current(); ok && opt.Group != "" && !m.filter.Focused() {
+				m.toggleGroup(opt.Group)
+			}
 		case m.keys.Select:
+			if m.canCreate() {
+				cmd := m.create() // Adds the option before the model is returned
+				return m, tea.Batch(cmd, m.selectVal)
+			}
+			if opt, ok := m.current(); ok && m.isHeader(opt) && !m.filter.Focused() {
+				m.toggleGroup(opt.Group) // Selecting a collapsed group expands it
+				break
+			}
 			if !m.filter.Focused() {
-				return m, m.selectVal
+				var cmd tea.Cmd
+				if opt, ok := m.current(); ok && !opt.Disabled {
+					m.selected = opt.Value
+					m.Validate()
+					cmd = m.remember(opt.Value)
+				}
+				return m, tea.Batch(cmd, m.selectVal)
 			} else {
 				m.filter.Blur()
 			}
+		case m.keys.Pin:
+			if opt, ok := m.current(); ok && !m.isHeader(opt) && !m.filter.Focused() && m.history != nil {
+				cmds = append(cmds, m.history.togglePin(m.name, opt.Value), m.rerank(opt.Value))
+			}
 		case m.keys.Filter:
 			if !m.filterHidden {
 				cmds = append(cmds, textinput.Blink)
@@ -106,14 +212,19 @@ func (m SelectorModel) Update(msg tea.Msg) (SelectorModel, tea.Cmd) {
 				return m, back(m.name)
 			}
 		}
+	case tea.MouseMsg:
+		cmds = append(cmds, m.mouse(msg))
 	}
 
-	// Reset the cursor when someone filters the list
-	if m.filter.Focused() {
-		m.cursor = 0
+	cmds = append(cmds, m.filterOptions()) // Use the filter to update the list of options
+	m.offset, _ = m.window()
+	if m.source != nil {
+		m.source.sync(m.filter.Value(), m.cursor, len(m.visibleOptions), &cmds)
+	}
+	if m.preview != nil {
+		opt, ok := m.current()
+		cmds = append(cmds, m.preview.update(opt, ok && !m.isHeader(opt)))
 	}
-
-	m.filterOptions() // Use the filter to update the list of options
 
 	return m, tea.Batch(cmds...)
 }
@@ -126,53 +237,242 @@ func (m SelectorModel) View() string {
 	if !m.filterHidden {
 		base.WriteString(rebuildCursor(m.filter.View(), m.filter.Focused(), m.theme))
 	}
-	if len(m.visibleOptions) == 0 {
+	if len(m.visibleOptions) == 0 && m.loadingOptions() {
+		base.WriteString(m.theme.Color("  Loading...\n", Neutral))
+	} else if m.canCreate() {
+		base.WriteString(renderCreate(m.createText(), m.theme))
+	} else if len(m.visibleOptions) == 0 {
 		base.WriteString("No options found \n")
 	} else {
-		for i, option := range m.visibleOptions {
-			if i == m.cursor {
+		start, end := m.window()
+		for i := start; i < end; i++ {
+			option := m.visibleOptions[i]
+			if m.hasHeader(i, start) {
+				base.WriteString(renderGroupHeader(option.Group, false, 0, m.theme) + "\n")
+			}
+
+			if i == m.cursor && !m.inactive {
 				base.WriteString(fmt.Sprintf("%s ", m.theme.ColorCond(">", Primary, !m.filter.Focused())))
 			} else {
 				base.WriteString(fmt.Sprintf("%s  ", strings.Repeat(" ", len(m.cursorIcon))))
 			}
 
+			if m.isHeader(option) {
+				base.WriteString(renderGroupHeader(option.Group, true, m.groupCounts[option.Group], m.theme) + "\n")
+				continue
+			}
+
 			var color ColorType
-			if option.Disabled {
+			if option.Disabled || m.disabled {
 				color = Neutral
+			} else if m.showSelected && m.selected != "" && option.Value == m.selected {
+				color = Success
 			}
 
-			base.WriteString(fmt.Sprintf("%s\n", m.theme.Color(option.Label, color)))
+			base.WriteString(fmt.Sprintf("%s\n", renderOption(option.Icon, option.Label, option.Description, matchesAt(m.visibleMatches, i), m.theme, color)))
+		}
+		if start > 0 || end < len(m.visibleOptions) {
+			base.WriteString(scrollIndicator(start, end, len(m.visibleOptions), m.theme))
 		}
-		if m.truncated {
-			base.WriteString(m.theme.Color(fmt.Sprintf("  Results limited, use %s to search...\n", m.keys.Filter), Neutral))
+		if m.loadingOptions() {
+			base.WriteString(m.theme.Color("  Loading...\n", Neutral))
+		}
+	}
+	if m.err != nil {
+		base.WriteString(fmt.Sprintf("  %s\n", m.theme.Color(m.err.Error(), Error)))
+	}
+
+	if m.preview != nil {
+		maxHeight := 0
+		if m.maxHeight != nil {
+			maxHeight = m.maxHeight()
 		}
+		return m.preview.view(base.String(), maxHeight, m.theme)
 	}
 
 	return base.String()
 }
 
-// Filters the possible options by the text contained in the textinput model
-func (m *SelectorModel) filterOptions() {
+// Filters the possible options by the text contained in the textinput model, ranking
+// them with the matcher. The options are only filtered again once the text or the options
+// change, returns a command when they are filtered in the background
+func (m *SelectorModel) filterOptions() tea.Cmd {
+	query := m.filter.Value()
+	if m.source != nil {
+		query = "" // The source is responsible for filtering
+	}
+
+	changed, cmd := m.filtering.update(query)
+	if changed {
+		m.layout()
+	}
+	return cmd
+}
+
+// Builds the visible options from the results of the filter, replacing collapsed groups
+// with a single header row. The cursor stays on the same option if it is still visible
+func (m *SelectorModel) layout() {
+	prev, hadPrev := m.current()
+
 	var visibleOptions SelectorOptions
-	for _, opt := range m.options {
-		filter := m.filter.Value()
-		if filter == "" || strings.Contains(strings.ToLower(opt.Label), strings.ToLower(filter)) {
-			visibleOptions = append(visibleOptions, opt)
+	var visibleMatches [][]int
+	m.groupCounts = make(map[string]int)
+	for i, idx := range m.filtering.indexes {
+		opt := m.options[idx]
+		opt.Group = m.filtering.groups[idx] // Pinned and recent options are shown in their own groups
+		m.groupCounts[opt.Group]++
+		if m.collapsed[opt.Group] { // Collapsed groups are replaced by a single header row
+			if m.groupCounts[opt.Group] == 1 {
+				visibleOptions = append(visibleOptions, SelectorOption{Label: opt.Group, Group: opt.Group})
+				visibleMatches = append(visibleMatches, nil)
+			}
+			continue
 		}
+		visibleOptions = append(visibleOptions, opt)
+		visibleMatches = append(visibleMatches, m.filtering.matches[i])
 	}
 
-	// If we have exceeded the max height, trim our results
-	if m.maxHeight != nil && len(visibleOptions) > 0 {
-		h := m.maxHeight() - 2 // Include the height of the filter input
-		if len(visibleOptions) > h {
-			visibleOptions = visibleOptions[:h]
-			m.truncated = true
-		} else {
-			m.truncated = false
+	m.visibleOptions = visibleOptions
+	m.visibleMatches = visibleMatches
+	m.restoreCursor(prev, hadPrev)
+}
+
+// Moves the cursor back onto the option it was on before the visible options changed, or
+// keeps it within the bounds of the list when the option is no longer visible
+func (m *SelectorModel) restoreCursor(prev SelectorOption, hadPrev bool) {
+	if hadPrev {
+		i := findIndex(m.visibleOptions, func(opt SelectorOption) bool {
+			if m.isHeader(prev) {
+				return m.isHeader(opt) && opt.Group == prev.Group
+			}
+			return !m.isHeader(opt) && opt.Value == prev.Value
+		})
+		if i != -1 {
+			m.cursor = i
+			return
 		}
 	}
+	m.cursor = min(max(m.cursor, 0), max(len(m.visibleOptions)-1, 0))
+}
 
-	m.visibleOptions = visibleOptions
+// Moves the cursor by several options at once, used for paging
+func (m *SelectorModel) jump(delta int) {
+	m.cursor = moveCursor(m.cursor, delta, len(m.visibleOptions))
+}
+
+// Returns the number of lines available to the options
+func (m SelectorModel) listHeight() int {
+	reserved := 0
+	if !m.filterHidden {
+		reserved++
+	}
+	if m.err != nil {
+		reserved++
+	}
+	if m.loadingOptions() {
+		reserved++
+	}
+	if m.preview != nil {
+		reserved += m.preview.reserved()
+	}
+	lines := 0
+	for i := range m.visibleOptions {
+		lines += m.optionLines(i, 0)
+		if m.maxHeight != nil && lines > m.maxHeight() {
+			break // The count makes no difference once the options overflow
+		}
+	}
+	return listHeight(m.maxHeight, reserved, lines)
+}
+
+// Indicates whether options are being loaded from the source or filtered in the background
+func (m SelectorModel) loadingOptions() bool {
+	return (m.source != nil && m.source.loading) || m.filtering.pending
+}
+
+// Returns the bounds of the options that are displayed in the scrolled window
+func (m SelectorModel) window() (int, int) {
+	return scrollWindow(m.offset, m.cursor, len(m.visibleOptions), m.listHeight(), m.optionLines)
+}
+
+// Returns the number of options that are displayed at once, used for paging
+func (m SelectorModel) pageSize() int {
+	start, end := m.window()
+	return max(end-start, 1)
+}
+
+// Returns the number of lines the option takes up when the window begins at start
+func (m SelectorModel) optionLines(i int, start int) int {
+	if m.hasHeader(i, start) {
+		return 2
+	}
+	return 1
+}
+
+// Indicates whether a group header is rendered above the option, which happens when a
+// new group begins and at the top of the window
+func (m SelectorModel) hasHeader(i int, start int) bool {
+	opt := m.visibleOptions[i]
+	if opt.Group == "" || m.isHeader(opt) {
+		return false
+	}
+	return i == start || m.visibleOptions[i-1].Group != opt.Group
+}
+
+// Indicates whether the option is the row that stands in for a collapsed group
+func (m SelectorModel) isHeader(opt SelectorOption) bool {
+	return opt.Group != "" && m.collapsed[opt.Group]
+}
+
+// Collapses or expands the group, keeping the cursor on the group
+func (m *SelectorModel) toggleGroup(group string) {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[group] = !m.collapsed[group]
+	m.layout()
+	m.cursor = max(findIndex(m.visibleOptions, func(opt SelectorOption) bool {
+		return opt.Group == group
+	}), 0)
+}
+
+// Indicates whether the filter text can be added as a new option, which is offered once
+// nothing matches it
+func (m SelectorModel) canCreate() bool {
+	return m.creatable && m.createText() != "" && len(m.visibleOptions) == 0 && !m.loadingOptions()
+}
+
+// The label and value of the option that would be created
+func (m SelectorModel) createText() string {
+	return strings.TrimSpace(m.filter.Value())
+}
+
+// Adds the filter text as a new option and selects it. Triggers the SelectorCreateMsg so
+// that the new option can be saved, along with the SelectMsg
+func (m *SelectorModel) create() tea.Cmd {
+	opt := SelectorOption{Label: m.createText(), Value: m.createText()}
+	m.setOptions(append(slices.Clone(m.options), opt))
+	m.selected = opt.Value
+	m.Validate()
+	m.clearFilter()
+	m.cursor = max(findIndex(m.visibleOptions, func(o SelectorOption) bool { return o.Value == opt.Value }), 0)
+	msg := SelectorCreateMsg{Name: m.name, Option: opt}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Triggered when the filter text is added as a new option
+type SelectorCreateMsg struct {
+	Name   string
+	Option SelectorOption
+}
+
+// Empties and blurs the filter, showing all of the options again
+func (m *SelectorModel) clearFilter() {
+	m.filter.SetValue("")
+	m.filter.Blur()
+	m.filterOptions() // Filtering by empty text never runs in the background
 }
 
 // Moves the cursor up or down among the options
@@ -188,9 +488,160 @@ func (m *SelectorModel) move(direction Direction) {
 	}
 }
 
+// Handles a mouse event relative to the top of the selector. Clicking an option selects
+// it and the wheel moves the cursor
+func (m *SelectorModel) mouse(msg tea.MouseMsg) tea.Cmd {
+	if m.Loading {
+		return nil
+	}
+	if direction, ok := scrolled(msg); ok {
+		m.move(direction)
+		return nil
+	}
+	if !clicked(msg) {
+		return nil
+	}
+
+	line := msg.Y
+	if !m.filterHidden {
+		if line == 0 {
+			m.filter.Focus()
+			return textinput.Blink
+		}
+		line--
+	}
+	if m.canCreate() {
+		if line == 0 {
+			cmd := m.create()
+			return tea.Batch(cmd, m.selectVal)
+		}
+		return nil
+	}
+
+	i := m.optionAt(line)
+	if i == -1 {
+		return nil
+	}
+	m.filter.Blur()
+	m.cursor = i
+	opt := m.visibleOptions[i]
+	if m.isHeader(opt) {
+		m.toggleGroup(opt.Group)
+		return nil
+	}
+	if opt.Disabled {
+		return nil
+	}
+	m.selected = opt.Value
+	m.Validate()
+	return tea.Batch(m.remember(opt.Value), m.selectVal)
+}
+
+// Returns the region taken up by the view of the selector
+func (m SelectorModel) Region() Region {
+	return viewRegion(m.View(), 0, 0)
+}
+
+// Returns the index of the visible option rendered on the line of the list, or -1
+func (m SelectorModel) optionAt(line int) int {
+	start, end := m.window()
+	return itemAt(line, start, end, m.optionLines)
+}
+
 // Sets options on the selector, triggered via the SelectorOptionsMsg
 func (m *SelectorModel) setOptions(options []SelectorOption) {
 	m.options = options
+
+	var groups map[string]string
+	var scores map[string]int
+	if m.history != nil {
+		values := make([]string, len(options))
+		for i, opt := range options {
+			values[i] = opt.Value
+		}
+		groups, scores = m.history.rank(m.name, values, m.recent)
+	}
+
+	targets := make([]filterTarget, len(options))
+	displayGroups := make([]string, len(options))
+	for i, opt := range options {
+		targets[i] = newFilterTarget(opt.Label, opt.Description, opt.Keywords)
+		targets[i].boost = scores[opt.Value]
+		displayGroups[i] = opt.Group
+		if group, ok := groups[opt.Value]; ok {
+			displayGroups[i] = group
+		} else if opt.Group == "" && len(groups) > 0 {
+			displayGroups[i] = otherGroup // Sets the remaining options apart from the recent ones
+		}
+	}
+	m.filtering.setTargets(targets, displayGroups)
+}
+
+// Records the chosen option in the history, which ranks the options again
+func (m *SelectorModel) remember(value string) tea.Cmd {
+	if m.history == nil {
+		return nil
+	}
+	return tea.Batch(m.history.record(m.name, value), m.rerank(value))
+}
+
+// Ranks the options again after the history changed, keeping the cursor on the option
+// with the value
+func (m *SelectorModel) rerank(value string) tea.Cmd {
+	m.setOptions(m.options)
+	cmd := m.filterOptions()
+	if i := findIndex(m.visibleOptions, func(opt SelectorOption) bool { return opt.Value == value }); i != -1 {
+		m.cursor = i
+	}
+	return cmd
+}
+
+// Returns the option under the cursor, if there is one
+func (m SelectorModel) current() (SelectorOption, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visibleOptions) {
+		return SelectorOption{}, false
+	}
+	return m.visibleOptions[m.cursor], true
+}
+
+// Returns the value of the selected option, or an empty string if there is none
+func (m SelectorModel) Value() any {
+	return m.selected
+}
+
+// Selects the option with the provided value, the value must be a string. The option
+// does not need to be loaded yet
+func (m *SelectorModel) SetValue(value any) error {
+	v, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("selector %q expects a string, got %T", m.name, value)
+	}
+	m.selected = v
+	return nil
+}
+
+func (m SelectorModel) Label() string {
+	return m.label
+}
+
+func (m SelectorModel) Disabled() bool {
+	return m.disabled
+}
+
+func (m *SelectorModel) SetDisabled(disabled bool) {
+	m.disabled = disabled
+}
+
+func (m *SelectorModel) Validate() error {
+	m.err = nil
+	if m.validate != nil {
+		m.err = m.validate(m.Value())
+	}
+	return m.err
+}
+
+func (m SelectorModel) Err() error {
+	return m.err
 }
 
 type SelectMsg struct {
@@ -199,12 +650,12 @@ type SelectMsg struct {
 
 // Chooses the value at the given index, triggers the SelectMsg for use in other models
 func (m *SelectorModel) selectVal() tea.Msg {
-	if !m.filter.Focused() {
-		val := m.visibleOptions[m.cursor].Value
+	if opt, ok := m.current(); ok && !m.filter.Focused() && !m.isHeader(opt) {
+		val := opt.Value
 		i := findIndex(m.options, func(opt SelectorOption) bool {
 			return opt.Value == val
 		})
-		if m.options[i].Disabled {
+		if i == -1 || m.options[i].Disabled {
 			return nil
 		}
 		return SelectMsg{m.options[i]}
@@ -0,0 +1,217 @@
+package boba
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SelectorField adapts the SelectorModel to the ComponentModel interface so that it can
+// be used in a Form. Its value is the value of the selected option. Moving up from the
+// first option or down from the last one hands focus off to the neighboring component
+type SelectorField struct {
+	SelectorModel
+	focused bool
+	noUp    bool
+	noDown  bool
+	initial string // The value selected when the field was created, restored by Reset
+}
+
+// Creates a SelectorModel that can be used as a component in a Form
+func NewSelectorField(opts NewSelectorModelOpts) ComponentModel {
+	m := SelectorField{SelectorModel: NewSelectorModel(opts), noUp: opts.NoUp, noDown: opts.NoDown}
+	m.initial = m.selected
+	m.inactive = true
+	m.showSelected = true
+	return &m
+}
+
+func (m SelectorField) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if !m.focused {
+			return &m, nil // Only the focused component handles keypresses
+		}
+		if !m.filter.Focused() && !m.disabled {
+			switch msg.String() {
+			case m.keys.Up:
+				if m.cursor == 0 && !m.noUp {
+					m.Blur()
+					m.Validate()
+					return &m, back(m.name)
+				}
+			case m.keys.Down:
+				if m.cursor >= len(m.visibleOptions)-1 && !m.noDown {
+					m.Blur()
+					m.Validate()
+					return &m, next(m.name)
+				}
+			case m.keys.Back:
+				m.Blur()
+				return &m, back(m.name)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.SelectorModel, cmd = m.SelectorModel.Update(msg)
+	return &m, cmd
+}
+
+// Renders the selector without its label or error, for use in a Form
+func (m SelectorField) fieldView() string {
+	m.err = nil
+	return strings.TrimSuffix(m.View(), "\n")
+}
+
+func (m SelectorField) Description() string {
+	return ""
+}
+
+func (m *SelectorField) Focus() tea.Cmd {
+	if m.disabled {
+		return nil
+	}
+	m.focused = true
+	m.inactive = false
+	return nil
+}
+
+func (m SelectorField) Focused() bool {
+	return m.focused
+}
+
+func (m *SelectorField) Blur() {
+	m.focused = false
+	m.inactive = true
+	m.filter.Blur()
+}
+
+func (m SelectorField) Id() string {
+	return m.name
+}
+
+func (m *SelectorField) Clear() {
+	m.selected = ""
+	m.cursor = 0
+	m.err = nil
+	m.filter.SetValue("")
+	m.filterOptions() // Filtering by empty text never runs in the background
+}
+
+func (m *SelectorField) Reset() {
+	m.Clear()
+	m.selected = m.initial
+}
+
+func (m SelectorField) Dirty() bool {
+	return m.selected != m.initial
+}
+
+// MultiSelectorField adapts the MultiSelectorModel to the ComponentModel interface so
+// that it can be used in a Form. Its value is the set of selected values. Moving up from
+// the first option or down from the last one hands focus off to the neighboring component
+type MultiSelectorField struct {
+	MultiSelectorModel
+	focused bool
+	noUp    bool
+	noDown  bool
+	initial []string // The values selected when the field was created, restored by Reset
+}
+
+// Creates a MultiSelectorModel that can be used as a component in a Form
+func NewMultiSelectorField(opts NewMultiSelectorModelOpts) ComponentModel {
+	m := MultiSelectorField{MultiSelectorModel: NewMultiSelectorModel(opts), noUp: opts.NoUp, noDown: opts.NoDown}
+	m.initial, _ = m.Value().([]string)
+	m.inactive = true
+	return &m
+}
+
+func (m MultiSelectorField) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if !m.focused {
+			return &m, nil // Only the focused component handles keypresses
+		}
+		if !m.filter.Focused() && !m.disabled {
+			switch msg.String() {
+			case m.keys.Up:
+				if m.cursor == 0 && !m.noUp {
+					m.Blur()
+					m.Validate()
+					return &m, back(m.name)
+				}
+			case m.keys.Down:
+				if m.cursor >= len(m.visibleOptions)-1 && !m.noDown {
+					m.Blur()
+					m.Validate()
+					return &m, next(m.name)
+				}
+			case m.keys.Back:
+				m.Blur()
+				return &m, back(m.name)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.MultiSelectorModel, cmd = m.MultiSelectorModel.Update(msg)
+	return &m, cmd
+}
+
+// Renders the multi-selector without its label or error, for use in a Form
+func (m MultiSelectorField) fieldView() string {
+	m.err = nil
+	return strings.TrimSuffix(m.View(), "\n")
+}
+
+func (m MultiSelectorField) Description() string {
+	return ""
+}
+
+func (m *MultiSelectorField) Focus() tea.Cmd {
+	if m.disabled {
+		return nil
+	}
+	m.focused = true
+	m.inactive = false
+	return nil
+}
+
+func (m MultiSelectorField) Focused() bool {
+	return m.focused
+}
+
+func (m *MultiSelectorField) Blur() {
+	m.focused = false
+	m.inactive = true
+	m.filter.Blur()
+}
+
+func (m MultiSelectorField) Id() string {
+	return m.name
+}
+
+func (m *MultiSelectorField) Clear() {
+	m.unselectAll()
+	m.cursor = 0
+	m.err = nil
+	m.filter.SetValue("")
+	m.filterOptions() // Filtering by empty text never runs in the background
+}
+
+func (m *MultiSelectorField) Reset() {
+	m.Clear()
+	_ = m.SetValue(m.initial)
+}
+
+func (m MultiSelectorField) Dirty() bool {
+	selected, _ := m.Value().([]string)
+	if len(selected) != len(m.initial) {
+		return true
+	}
+	for i := range selected {
+		if selected[i] != m.initial[i] {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package boba
 
 import (
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -44,6 +45,40 @@ type ComponentModel interface {
 	Id() string
 }
 
+// Components that remember the value they were created with implement the
+// Resettable interface, which allows forms to track unsaved changes
+type Resettable interface {
+	Reset()      // Restores the initial value
+	Dirty() bool // Indicates whether the value differs from the initial value
+}
+
+// Components that implement the ValueSetter interface can have their value set
+// programmatically, for instance when a form is prefilled from a draft
+type ValueSetter interface {
+	SetValue(value any) error
+}
+
+// Field extends the ComponentModel with labels, enabled/disabled state and validation.
+// It is detected at runtime, so components that only implement the ComponentModel
+// can still be used in a Form. Disabled fields are skipped when moving focus.
+type Field interface {
+	ComponentModel
+	ValueSetter
+	Label() string
+	Disabled() bool
+	SetDisabled(disabled bool)
+	Validate() error // Runs the field's validation function against its value
+	Err() error      // Returns the error from the last validation, if any
+}
+
+// Signature of the validation functions that can be provided to fields
+type ValidateFunc func(value any) error
+
+// Sets the values of the components in a Form, keyed by their Id
+type SetValuesMsg struct {
+	Values map[string]any
+}
+
 type Direction string
 
 const (
@@ -52,8 +87,11 @@ const (
 )
 
 type FilterOpts struct {
-	Placeholder string
-	Hidden      bool
+	Placeholder    string
+	Hidden         bool
+	Matcher        Matcher       // Defaults to the ContainsMatcher
+	AsyncThreshold int           // Options are filtered in the background once there are more than this, defaults to 10000
+	Debounce       time.Duration // How long the filter text must stop changing before the options are filtered, defaults to 50ms
 }
 
 // Used to re-color the cursor that bubbletea provides, ugh
@@ -0,0 +1,190 @@
+package boba
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A page of options returned by a Source
+type SourcePage[O any] struct {
+	Options []O
+	More    bool // Whether there are further pages after this one
+}
+
+// A Source loads the options of a selector on demand, for instance from a remote API.
+// It receives the current filter text and the index of the page to load. The context is
+// cancelled when the request becomes stale, e.g. because the filter text changed
+type Source[O any] interface {
+	Fetch(ctx context.Context, query string, page int) (SourcePage[O], error)
+}
+
+// Adapts a function to the Source interface
+type SourceFunc[O any] func(ctx context.Context, query string, page int) (SourcePage[O], error)
+
+func (f SourceFunc[O]) Fetch(ctx context.Context, query string, page int) (SourcePage[O], error) {
+	return f(ctx, query, page)
+}
+
+// Configures how a selector loads its options from a Source
+type SourceOpts[O any] struct {
+	Source   Source[O]
+	Debounce time.Duration // How long to wait after the filter text changes before querying, defaults to 300ms
+	Prefetch int           // How close the cursor gets to the end of the list before the next page is loaded, defaults to 5
+}
+
+const (
+	defaultSourceDebounce = 300 * time.Millisecond
+	defaultSourcePrefetch = 5
+)
+
+// Used to tell apart the messages of different selectors in the same program
+var sourceIds atomic.Int64
+
+// Fired once the filter text has stopped changing for the debounce duration
+type sourceDebounceMsg struct {
+	id  int64
+	seq int
+}
+
+// Contains the result of a request made to a Source
+type sourcePageMsg[O any] struct {
+	id     int64
+	seq    int
+	page   int
+	result SourcePage[O]
+	err    error
+}
+
+// Tracks the requests that a selector makes to its Source. Every new query increments
+// the sequence number, so that responses to stale queries can be dropped
+type sourceLoader[O any] struct {
+	id       int64
+	source   Source[O]
+	debounce time.Duration
+	prefetch int
+	query    string
+	seq      int
+	page     int
+	more     bool
+	loading  bool
+	started  bool // Whether the first page has been requested, by Init or by the first update
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func newSourceLoader[O any](opts SourceOpts[O]) *sourceLoader[O] {
+	if opts.Source == nil {
+		return nil
+	}
+	if opts.Debounce == 0 {
+		opts.Debounce = defaultSourceDebounce
+	}
+	if opts.Prefetch == 0 {
+		opts.Prefetch = defaultSourcePrefetch
+	}
+	return &sourceLoader[O]{
+		id:       sourceIds.Add(1),
+		source:   opts.Source,
+		debounce: opts.Debounce,
+		prefetch: opts.Prefetch,
+	}
+}
+
+// Cancels any requests for the previous query and loads the first page of the current one
+func (l *sourceLoader[O]) load() tea.Cmd {
+	l.started = true
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	return l.fetch(0)
+}
+
+// Waits for the debounce duration before querying the source with the new filter text.
+// Returns nil if the filter text has not changed
+func (l *sourceLoader[O]) setQuery(query string) tea.Cmd {
+	if query == l.query {
+		return nil
+	}
+	l.query = query
+	l.seq++
+	id, seq := l.id, l.seq
+	return tea.Tick(l.debounce, func(time.Time) tea.Msg {
+		return sourceDebounceMsg{id: id, seq: seq}
+	})
+}
+
+// Loads the next page once the cursor gets close to the end of the options
+func (l *sourceLoader[O]) loadMore(cursor int, total int) tea.Cmd {
+	if !l.more || l.loading || cursor < total-l.prefetch {
+		return nil
+	}
+	return l.fetch(l.page + 1)
+}
+
+// Requests a page from the source, the request is cancelled along with its query
+func (l *sourceLoader[O]) fetch(page int) tea.Cmd {
+	l.loading = true
+	ctx, id, seq, query, source := l.ctx, l.id, l.seq, l.query, l.source
+	return func() tea.Msg {
+		result, err := source.Fetch(ctx, query, page)
+		return sourcePageMsg[O]{id: id, seq: seq, page: page, result: result, err: err}
+	}
+}
+
+// Indicates whether the message belongs to the latest query of this loader
+func (l *sourceLoader[O]) isCurrent(id int64, seq int) bool {
+	return l.id == id && l.seq == seq
+}
+
+// Records the loaded page and returns its options, merged with the existing ones when
+// the page follows a previous one
+func (l *sourceLoader[O]) receive(msg sourcePageMsg[O], existing []O) []O {
+	l.loading = false
+	if msg.err != nil {
+		l.more = false // A failed page is not requested again until the query changes
+		return existing
+	}
+	l.page = msg.page
+	l.more = msg.result.More
+	if msg.page == 0 {
+		return msg.result.Options
+	}
+	return append(existing, msg.result.Options...)
+}
+
+// Handles the messages of the loader. Returns the options of the selector when a page of
+// the current query arrives, which replace or extend the existing ones
+func (l *sourceLoader[O]) update(msg tea.Msg, existing []O, cmds *[]tea.Cmd) ([]O, bool) {
+	switch msg := msg.(type) {
+	case sourceDebounceMsg:
+		if l.isCurrent(msg.id, msg.seq) {
+			*cmds = append(*cmds, l.load())
+		}
+	case sourcePageMsg[O]:
+		if !l.isCurrent(msg.id, msg.seq) {
+			return nil, false // Drop responses to stale queries
+		}
+		if msg.err != nil && !errors.Is(msg.err, context.Canceled) {
+			*cmds = append(*cmds, func() tea.Msg { return ErrMsg{msg.err} })
+		}
+		return l.receive(msg, existing), msg.err == nil
+	}
+	return nil, false
+}
+
+// Queues a request for the current filter text, and for the next page when the cursor
+// nears the end of the options. The first page is loaded here when the selector was never
+// initialized, e.g. when it is a field in a Form
+func (l *sourceLoader[O]) sync(query string, cursor int, total int, cmds *[]tea.Cmd) {
+	if !l.started {
+		l.query = query
+		*cmds = append(*cmds, l.load())
+		return
+	}
+	*cmds = append(*cmds, l.setQuery(query), l.loadMore(cursor, total))
+}
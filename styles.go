@@ -11,6 +11,7 @@ type Colors struct {
 	Neutral   string `mapstructure:"neutral"`
 	Primary   string `mapstructure:"primary"`
 	Secondary string `mapstructure:"secondary"`
+	Error     string `mapstructure:"error"`
 }
 
 // Possible types of colors
@@ -19,6 +20,7 @@ const (
 	Primary   ColorType = "Primary"
 	Neutral   ColorType = "Neutral"
 	Secondary ColorType = "Secondary"
+	Error     ColorType = "Error"
 )
 
 // Used to set colors and styles, e.g. t.color("some-text", Success)
@@ -31,6 +33,7 @@ func NewTheme(overrides Colors) Theme {
 		Secondary: "#FFA066",
 		Neutral:   "#979797",
 		Success:   "#98BB6C",
+		Error:     "#E46876",
 	}
 	if overrides.Primary != "" {
 		defaultColors[Primary] = overrides.Primary
@@ -44,6 +47,9 @@ func NewTheme(overrides Colors) Theme {
 	if overrides.Success != "" {
 		defaultColors[Success] = overrides.Success
 	}
+	if overrides.Error != "" {
+		defaultColors[Error] = overrides.Error
+	}
 	t := make(Theme)
 	for key, color := range defaultColors {
 		t[key] = lipgloss.NewStyle().Foreground(lipgloss.Color(color))
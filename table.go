@@ -0,0 +1,603 @@
+package boba
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A column of the TableModel. The cells of the column are rendered from each row by Value,
+// and are sorted by their text unless a Compare function is provided
+type TableColumn[R any] struct {
+	Title    string
+	Value    func(row R) string
+	Compare  func(a R, b R) int // Orders the rows when sorting by the column, e.g. for numbers or dates
+	Width    int                // Fixes the width of the column, otherwise it is sized to its contents
+	MinWidth int                // The narrowest the column is shrunk to when the table does not fit, defaults to the width of the title
+	Right    bool               // Aligns the cells to the right
+}
+
+// Used to set the rows of the table
+type TableRowsMsg[R any] struct {
+	Rows []R
+}
+
+// Triggered when a row is chosen in a single-select table
+type TableSelectMsg[R any] struct {
+	Name string
+	Row  R
+}
+
+// Triggered when a row is toggled in a multi-select table
+type TableToggleMsg[R any] struct {
+	Name     string
+	Row      R
+	Selected bool
+}
+
+// Triggered by the select key in a multi-select table, contains the selected rows
+type TableSubmitMsg[R any] struct {
+	Name string
+	Rows []R
+}
+
+// Marks the cells that are cut off to fit the width of their column
+const ellipsis = "…"
+
+type TableModel[R any] struct {
+	columns      []TableColumn[R]
+	rows         []R
+	cells        [][]matchText // The text of each cell, by row and column, prepared for matching
+	cellWidths   []int         // The width of the widest cell in each column
+	stale        bool          // Whether the rows, filters or sorting changed since the last refresh
+	visible      []int         // Indexes of the rows that pass the filters, in sorted order
+	matches      [][][]int     // Positions of the runes matched by the filters, by row and column
+	checked      []bool        // The selected rows of a multi-select table, by index
+	chosen       int           // The index of the selected row of a single-select table, or -1
+	cursor       int
+	offset       int
+	column       int // The column that is sorted and filtered by the keys
+	sortColumn   int // The column the rows are sorted by, or -1
+	descending   bool
+	filters      []string // The filter text of each column
+	filter       textinput.Model
+	filterHidden bool
+	placeholder  string
+	matcher      Matcher
+	keys         KeyOpts
+	theme        Theme
+	name         string
+	maxHeight    func() int
+	width        func() int
+	multi        bool
+}
+
+type NewTableModelOpts[R any] struct {
+	Columns   []TableColumn[R]
+	Rows      []R
+	Filter    FilterOpts
+	Theme     Theme
+	Name      string
+	Keys      KeyOpts
+	MaxHeight func() int
+	Width     func() int // The width the columns are fitted to, otherwise they are sized to their contents
+	Multi     bool       // Allows for several rows to be selected
+}
+
+// Displays records as rows with a column for each of their attributes. The rows can be
+// sorted by a column and filtered by the text of each column, the left and right keys
+// choose the column. The selection triggers the TableSelectMsg, or the TableToggleMsg
+// and TableSubmitMsg when multiple rows can be selected
+func NewTableModel[R any](opts NewTableModelOpts[R]) TableModel[R] {
+	m := TableModel[R]{
+		columns:      opts.Columns,
+		chosen:       -1,
+		sortColumn:   -1,
+		filters:      make([]string, len(opts.Columns)),
+		filterHidden: opts.Filter.Hidden,
+		placeholder:  opts.Filter.Placeholder,
+		matcher:      opts.Filter.Matcher,
+		keys:         opts.Keys,
+		theme:        opts.Theme,
+		name:         opts.Name,
+		maxHeight:    opts.MaxHeight,
+		width:        opts.Width,
+		multi:        opts.Multi,
+	}
+
+	if !opts.Filter.Hidden {
+		m.filter = textinput.New()
+	}
+
+	m.setRows(opts.Rows)
+	m.focusColumn(0)
+	m.refresh()
+	return m
+}
+
+func (m TableModel[R]) Init() tea.Cmd {
+	return nil
+}
+
+func (m TableModel[R]) Update(msg tea.Msg) (TableModel[R], tea.Cmd) {
+	var cmds []tea.Cmd
+
+	m.filter = UpdateSubmodel(m.filter, msg, &cmds)
+
+	switch msg := msg.(type) {
+	case TableRowsMsg[R]:
+		m.setRows(msg.Rows)
+	case tea.KeyMsg:
+		if m.filter.Focused() {
+			switch msg.String() {
+			case m.keys.Select, m.keys.Back:
+				m.filter.Blur()
+			}
+			break
+		}
+
+		switch msg.String() {
+		case m.keys.Down:
+			m.cursor = moveCursor(m.cursor, 1, len(m.visible))
+		case m.keys.Up:
+			m.cursor = moveCursor(m.cursor, -1, len(m.visible))
+		case m.keys.PageDown:
+			m.cursor = moveCursor(m.cursor, m.pageSize(), len(m.visible))
+		case m.keys.PageUp:
+			m.cursor = moveCursor(m.cursor, -m.pageSize(), len(m.visible))
+		case m.keys.Home:
+			m.cursor = 0
+		case m.keys.End:
+			m.cursor = max(len(m.visible)-1, 0)
+		case m.keys.Left:
+			m.focusColumn(m.column - 1)
+		case m.keys.Right:
+			m.focusColumn(m.column + 1)
+		case m.keys.Sort:
+			m.sortBy(m.column)
+		case m.keys.Toggle:
+			if m.multi {
+				cmds = append(cmds, m.toggle())
+			}
+		case m.keys.Select:
+			if m.multi {
+				cmds = append(cmds, m.submit)
+			} else {
+				cmds = append(cmds, m.selectRow())
+			}
+		case m.keys.Filter:
+			if !m.filterHidden {
+				cmds = append(cmds, textinput.Blink)
+				m.filter.Focus()
+			}
+		case m.keys.Back:
+			return m, back(m.name)
+		}
+	case tea.MouseMsg:
+		cmds = append(cmds, m.mouse(msg))
+	}
+
+	if !m.filterHidden && len(m.columns) > 0 && m.filters[m.column] != m.filter.Value() {
+		m.filters[m.column] = m.filter.Value()
+		m.stale = true
+	}
+	if m.stale {
+		m.refresh()
+	}
+	m.cursor = min(m.cursor, max(len(m.visible)-1, 0))
+	m.offset, _ = m.window()
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m TableModel[R]) View() string {
+	base := strings.Builder{}
+	if !m.filterHidden {
+		base.WriteString(rebuildCursor(m.filter.View(), m.filter.Focused(), m.theme))
+	}
+
+	widths := m.widths()
+	base.WriteString(m.headerView(widths) + "\n")
+	if len(m.visible) == 0 {
+		base.WriteString("No rows found \n")
+		return base.String()
+	}
+
+	start, end := m.window()
+	for i := start; i < end; i++ {
+		base.WriteString(m.rowView(i, widths) + "\n")
+	}
+
+	if start > 0 || end < len(m.visible) {
+		base.WriteString(scrollIndicator(start, end, len(m.visible), m.theme))
+	}
+
+	return base.String()
+}
+
+// Renders the titles of the columns. The focused column is highlighted, and the titles
+// are marked when the rows are sorted or filtered by their column
+func (m TableModel[R]) headerView(widths []int) string {
+	cells := make([]string, len(m.columns))
+	for c, col := range m.columns {
+		color := Secondary
+		if c == m.column {
+			color = Primary
+		}
+		cells[c] = fitCell(m.title(c), nil, widths[c], col.Right, m.theme, color)
+	}
+	return strings.Repeat(" ", m.prefixWidth()) + strings.Join(cells, "  ")
+}
+
+// Renders the visible row at index i
+func (m TableModel[R]) rowView(i int, widths []int) string {
+	idx := m.visible[i]
+
+	base := strings.Builder{}
+	if i == m.cursor {
+		base.WriteString(m.theme.ColorCond("> ", Primary, !m.filter.Focused()))
+	} else {
+		base.WriteString("  ")
+	}
+
+	var color ColorType
+	if m.multi {
+		icon := "[ ]"
+		if m.checked[idx] {
+			icon = "[x]"
+		}
+		base.WriteString(icon + " ")
+	} else if idx == m.chosen {
+		color = Success
+	}
+
+	cells := make([]string, len(m.columns))
+	for c, col := range m.columns {
+		cells[c] = fitCell(m.cells[idx][c].text, m.matches[idx][c], widths[c], col.Right, m.theme, color)
+	}
+	base.WriteString(strings.Join(cells, "  "))
+	return base.String()
+}
+
+// Returns the title of the column along with markers for sorting and filtering
+func (m TableModel[R]) title(c int) string {
+	title := m.columns[c].Title
+	if m.filters[c] != "" {
+		title += "*"
+	}
+	if c == m.sortColumn && m.descending {
+		title += " ▼"
+	} else if c == m.sortColumn {
+		title += " ▲"
+	}
+	return title
+}
+
+// Cuts the text off at the width and pads it to the width, highlighting the matched runes
+func fitCell(text string, matches []int, width int, right bool, theme Theme, color ColorType) string {
+	suffix := ""
+	width = max(width, 1) // Leaves room for the ellipsis
+	if lipgloss.Width(text) > width {
+		runes, used := []rune(text), 0
+		for i, r := range runes {
+			used += lipgloss.Width(string(r))
+			if used > width-lipgloss.Width(ellipsis) {
+				text = string(runes[:i])
+				break
+			}
+		}
+		suffix = theme.Color(ellipsis, color)
+		kept := len([]rune(text))
+		matches = slices.DeleteFunc(slices.Clone(matches), func(i int) bool { return i >= kept })
+	}
+
+	used := lipgloss.Width(text)
+	if suffix != "" {
+		used += lipgloss.Width(ellipsis)
+	}
+	cell := highlight(text, matches, theme, color) + suffix
+	padding := strings.Repeat(" ", max(width-used, 0))
+	if right {
+		return padding + cell
+	}
+	return cell + padding
+}
+
+// Returns the width of each column. Columns are as wide as their contents unless their width
+// is fixed. When the table is wider than the available width, the widest columns are shrunk
+// until it fits or every column is at its minimum width
+func (m TableModel[R]) widths() []int {
+	widths := make([]int, len(m.columns))
+	mins := make([]int, len(m.columns))
+	for c, col := range m.columns {
+		if col.Width > 0 {
+			widths[c], mins[c] = col.Width, col.Width
+			continue
+		}
+		widths[c] = max(lipgloss.Width(m.title(c)), m.cellWidths[c], 1)
+		minWidth := col.MinWidth
+		if minWidth == 0 {
+			minWidth = lipgloss.Width(col.Title)
+		}
+		mins[c] = min(widths[c], max(minWidth, 1))
+	}
+	if m.width == nil {
+		return widths
+	}
+
+	over := m.prefixWidth() + 2*max(len(widths)-1, 0) - m.width()
+	for _, w := range widths {
+		over += w
+	}
+	for ; over > 0; over-- {
+		widest := -1
+		for c := range widths {
+			if widths[c] > mins[c] && (widest == -1 || widths[c] > widths[widest]) {
+				widest = c
+			}
+		}
+		if widest == -1 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// Returns the width taken up by the cursor and checkboxes before the cells of each row
+func (m TableModel[R]) prefixWidth() int {
+	if m.multi {
+		return 6
+	}
+	return 2
+}
+
+// Filters the rows by the filter text of each column and sorts them, keeping the cursor
+// on the same row if it is still visible. Only called once the rows, filters or sorting change
+func (m *TableModel[R]) refresh() {
+	m.stale = false
+	prev, hadPrev := m.current()
+
+	match := resolveMatcher(m.matcher)
+	queries := make([]matchText, len(m.filters))
+	for c, filter := range m.filters {
+		queries[c] = newMatchText(filter)
+	}
+
+	m.visible = make([]int, 0, len(m.rows))
+	m.matches = make([][][]int, len(m.rows))
+	for i := range m.rows {
+		m.matches[i] = make([][]int, len(m.columns))
+		ok := true
+		for c, query := range queries {
+			if query.text == "" {
+				continue
+			}
+			var matches []int
+			if _, matches, ok = match(query, m.cells[i][c]); !ok {
+				break
+			}
+			m.matches[i][c] = matches
+		}
+		if ok {
+			m.visible = append(m.visible, i)
+		}
+	}
+
+	if m.sortColumn != -1 {
+		col := m.columns[m.sortColumn]
+		slices.SortStableFunc(m.visible, func(a int, b int) int {
+			order := col.compare(m.rows[a], m.rows[b])
+			if m.descending {
+				return -order
+			}
+			return order
+		})
+	}
+
+	if hadPrev {
+		if i := slices.Index(m.visible, prev); i != -1 {
+			m.cursor = i
+		}
+	}
+}
+
+func (col TableColumn[R]) compare(a R, b R) int {
+	if col.Compare != nil {
+		return col.Compare(a, b)
+	}
+	return strings.Compare(col.Value(a), col.Value(b))
+}
+
+// Sorts the rows by the column in ascending order, then in descending order, and then
+// restores their original order
+func (m *TableModel[R]) sortBy(c int) {
+	m.stale = true
+	switch {
+	case m.sortColumn != c:
+		m.sortColumn, m.descending = c, false
+	case !m.descending:
+		m.descending = true
+	default:
+		m.sortColumn = -1
+	}
+}
+
+// Focuses the column, whose filter text is then edited by the filter input
+func (m *TableModel[R]) focusColumn(c int) {
+	if len(m.columns) == 0 {
+		return
+	}
+	m.column = min(max(c, 0), len(m.columns)-1)
+	if m.filterHidden {
+		return
+	}
+	m.filter.SetValue(m.filters[m.column])
+	m.filter.Placeholder = m.placeholder
+	if m.placeholder == "" {
+		m.filter.Placeholder = fmt.Sprintf("Filter %s...", m.columns[m.column].Title)
+	}
+}
+
+// Replaces the rows of the table, which clears the selection. The text of the cells is
+// rendered once here rather than every time the rows are filtered or displayed
+func (m *TableModel[R]) setRows(rows []R) {
+	m.rows = rows
+	m.checked = make([]bool, len(rows))
+	m.chosen = -1
+	m.stale = true
+
+	m.cells = make([][]matchText, len(rows))
+	m.cellWidths = make([]int, len(m.columns))
+	for i, row := range rows {
+		m.cells[i] = make([]matchText, len(m.columns))
+		for c, col := range m.columns {
+			m.cells[i][c] = newMatchText(col.Value(row))
+			m.cellWidths[c] = max(m.cellWidths[c], lipgloss.Width(m.cells[i][c].text))
+		}
+	}
+}
+
+// Returns the index of the row under the cursor
+func (m TableModel[R]) current() (int, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return 0, false
+	}
+	return m.visible[m.cursor], true
+}
+
+// Chooses the row under the cursor, triggering the TableSelectMsg
+func (m *TableModel[R]) selectRow() tea.Cmd {
+	idx, ok := m.current()
+	if !ok {
+		return nil
+	}
+	m.chosen = idx
+	msg := TableSelectMsg[R]{Name: m.name, Row: m.rows[idx]}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Selects or unselects the row under the cursor, triggering the TableToggleMsg
+func (m *TableModel[R]) toggle() tea.Cmd {
+	idx, ok := m.current()
+	if !ok {
+		return nil
+	}
+	m.checked[idx] = !m.checked[idx]
+	msg := TableToggleMsg[R]{Name: m.name, Row: m.rows[idx], Selected: m.checked[idx]}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+func (m *TableModel[R]) submit() tea.Msg {
+	return TableSubmitMsg[R]{Name: m.name, Rows: m.SelectedRows()}
+}
+
+// Handles a mouse event relative to the top of the table. Clicking a title sorts the rows
+// by its column, clicking a row selects or toggles it, and the wheel moves the cursor
+func (m *TableModel[R]) mouse(msg tea.MouseMsg) tea.Cmd {
+	if direction, ok := scrolled(msg); ok {
+		step := 1
+		if direction == Up {
+			step = -1
+		}
+		m.cursor = moveCursor(m.cursor, step, len(m.visible))
+		return nil
+	}
+	if !clicked(msg) {
+		return nil
+	}
+
+	line := msg.Y
+	if !m.filterHidden {
+		if line == 0 {
+			m.filter.Focus()
+			return textinput.Blink
+		}
+		line--
+	}
+	m.filter.Blur()
+
+	if line == 0 {
+		if c := m.columnAt(msg.X); c != -1 {
+			m.focusColumn(c)
+			m.sortBy(c)
+		}
+		return nil
+	}
+
+	start, end := m.window()
+	i := itemAt(line-1, start, end, func(int, int) int { return 1 })
+	if i == -1 {
+		return nil
+	}
+	m.cursor = i
+	if m.multi {
+		return m.toggle()
+	}
+	return m.selectRow()
+}
+
+// Returns the region taken up by the view of the table
+func (m TableModel[R]) Region() Region {
+	return viewRegion(m.View(), 0, 0)
+}
+
+// Returns the column rendered at the horizontal position, or -1
+func (m TableModel[R]) columnAt(x int) int {
+	x -= m.prefixWidth()
+	for c, w := range m.widths() {
+		if x >= 0 && x < w {
+			return c
+		}
+		x -= w + 2
+	}
+	return -1
+}
+
+// Returns the number of lines available to the rows
+func (m TableModel[R]) listHeight() int {
+	reserved := 1 // The titles of the columns
+	if !m.filterHidden {
+		reserved++
+	}
+	return listHeight(m.maxHeight, reserved, len(m.visible))
+}
+
+// Returns the bounds of the rows that are displayed in the scrolled window
+func (m TableModel[R]) window() (int, int) {
+	return scrollWindow(m.offset, m.cursor, len(m.visible), m.listHeight(), func(int, int) int { return 1 })
+}
+
+// Returns the number of rows that are displayed at once, used for paging
+func (m TableModel[R]) pageSize() int {
+	start, end := m.window()
+	return max(end-start, 1)
+}
+
+// Returns the chosen row of a single-select table
+func (m TableModel[R]) Selected() (R, bool) {
+	if m.chosen == -1 {
+		var zero R
+		return zero, false
+	}
+	return m.rows[m.chosen], true
+}
+
+// Returns the selected rows of a multi-select table, in their original order
+func (m TableModel[R]) SelectedRows() []R {
+	var rows []R
+	for i, row := range m.rows {
+		if m.checked[i] {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,269 @@
+package boba
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type TextAreaModel struct {
+	theme       Theme
+	id          string
+	label       string
+	description string
+	initial     string
+	input       textarea.Model
+	width       func() int
+	charLimit   int
+	lineLimit   int
+	noUp        bool
+	noDown      bool
+	disabled    bool
+	validate    ValidateFunc
+	err         error
+	keys        KeyOpts
+}
+
+type NewTextAreaOptions struct {
+	NoDown      bool
+	NoUp        bool
+	Id          string
+	Label       string
+	Description string // Help text rendered beneath the text area
+	Placeholder string
+	Value       string     // The initial value of the text area, restored by Reset
+	Width       func() int // The width the text is wrapped to, including the line numbers
+	Height      int        // The number of lines that are displayed at once, defaults to 6
+	LineNumbers bool
+	CharLimit   int // The maximum number of characters, shown in a counter beneath the text
+	LineLimit   int // The maximum number of lines, shown in a counter beneath the text
+	Disabled    bool
+	Validate    ValidateFunc // Run when focus leaves the text area, and on every change while invalid
+	Theme       Theme
+	Keys        KeyOpts
+}
+
+// Wrapper around the textarea model from BubbleTea for editing text that spans several
+// lines, such as commit messages. Long lines are wrapped to the width. Moving up from the
+// first line or down from the last one hands off focus to the nearest ComponentModel
+func NewTextAreaModel(opts NewTextAreaOptions) ComponentModel {
+	ta := TextAreaModel{
+		input:       textarea.New(),
+		id:          opts.Id,
+		label:       opts.Label,
+		description: opts.Description,
+		initial:     opts.Value,
+		width:       opts.Width,
+		charLimit:   opts.CharLimit,
+		lineLimit:   opts.LineLimit,
+		noUp:        opts.NoUp,
+		noDown:      opts.NoDown,
+		disabled:    opts.Disabled,
+		validate:    opts.Validate,
+		theme:       opts.Theme,
+		keys:        opts.Keys,
+	}
+	ta.input.Placeholder = opts.Placeholder
+	ta.input.ShowLineNumbers = opts.LineNumbers
+	ta.input.CharLimit = opts.CharLimit
+	ta.input.MaxHeight = max(ta.input.MaxHeight, opts.LineLimit) // Sizes the line numbers, the LineLimit is enforced in Update
+	if opts.Height > 0 {
+		ta.input.SetHeight(opts.Height)
+	}
+	if opts.Width != nil {
+		ta.input.SetWidth(opts.Width())
+	}
+
+	ta.input.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.input.FocusedStyle.Prompt = opts.Theme[Primary]
+	ta.input.BlurredStyle.Prompt = opts.Theme[Neutral]
+	ta.input.SetValue(opts.Value)
+	return &ta
+}
+
+func (m TextAreaModel) Init() tea.Cmd {
+	return m.input.Focus()
+}
+
+func (m TextAreaModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	if m.disabled {
+		return &m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && m.Focused() { // Handled before the cursor moves
+		switch msg.String() {
+		case m.keys.Up:
+			if m.firstRow() && !m.noUp {
+				m.Blur()
+				m.Validate()
+				return &m, back(m.id)
+			}
+		case m.keys.Down:
+			if m.lastRow() && !m.noDown {
+				m.Blur()
+				m.Validate()
+				return &m, next(m.id)
+			}
+		case m.keys.Back:
+			m.Blur()
+			return &m, nil
+		}
+	}
+
+	if m.width != nil {
+		m.input.SetWidth(m.width())
+	}
+
+	var cmds = []tea.Cmd{}
+
+	prev := m.input
+	m.input = UpdateSubmodel(m.input, msg, &cmds)
+	if m.lineLimit > 0 && m.input.LineCount() > m.lineLimit {
+		m.input = prev // Drops the change that went past the line limit
+	}
+
+	if m.err != nil { // Clear the error as soon as the user fixes the value
+		m.Validate()
+	}
+
+	return &m, tea.Batch(cmds...)
+}
+
+// Indicates whether the cursor is on the first row of the text, including wrapped rows
+func (m TextAreaModel) firstRow() bool {
+	return m.input.Line() == 0 && m.input.LineInfo().RowOffset == 0
+}
+
+// Indicates whether the cursor is on the last row of the text, including wrapped rows
+func (m TextAreaModel) lastRow() bool {
+	info := m.input.LineInfo()
+	return m.input.Line() == m.input.LineCount()-1 && info.RowOffset >= info.Height-1
+}
+
+func (m TextAreaModel) View() string {
+	base := strings.Builder{}
+	if m.label != "" {
+		base.WriteString(fmt.Sprintf("%s\n", m.theme.ColorCond(m.label, Primary, m.Focused())))
+	}
+	base.WriteString(m.fieldView() + "\n")
+	if m.description != "" {
+		base.WriteString(fmt.Sprintf("  %s\n", m.theme.Color(m.description, Neutral)))
+	}
+	if m.err != nil {
+		base.WriteString(fmt.Sprintf("  %s\n", m.theme.Color(m.err.Error(), Error)))
+	}
+	return base.String()
+}
+
+// Renders the text area and its counter without the label or description, for use in a Form
+func (m TextAreaModel) fieldView() string {
+	view := m.input.View()
+	if m.disabled {
+		view = m.theme.Color(view, Neutral)
+	}
+	if counter := m.counterView(); counter != "" {
+		view += "\n" + counter
+	}
+	return view
+}
+
+// Renders the number of characters and lines used out of their limits, e.g. "120/500
+// characters, 4/10 lines". Empty when there are no limits
+func (m TextAreaModel) counterView() string {
+	var counts []string
+	if m.charLimit > 0 {
+		counts = append(counts, fmt.Sprintf("%d/%d characters", m.input.Length(), m.charLimit))
+	}
+	if m.lineLimit > 0 {
+		counts = append(counts, fmt.Sprintf("%d/%d lines", m.input.LineCount(), m.lineLimit))
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	full := (m.charLimit > 0 && m.input.Length() >= m.charLimit) || (m.lineLimit > 0 && m.input.LineCount() >= m.lineLimit)
+	if full {
+		return m.theme.Color(strings.Join(counts, ", "), Secondary)
+	}
+	return m.theme.Color(strings.Join(counts, ", "), Neutral)
+}
+
+func (m TextAreaModel) Label() string {
+	return m.label
+}
+
+func (m TextAreaModel) Description() string {
+	return m.description
+}
+
+func (m *TextAreaModel) Blur() {
+	m.input.Blur()
+}
+
+func (m *TextAreaModel) Clear() {
+	m.input.SetValue("")
+	m.err = nil
+}
+
+// Sets the text of the text area, the value must be a string
+func (m *TextAreaModel) SetValue(value any) error {
+	v, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("text area %q expects a string, got %T", m.id, value)
+	}
+	m.input.SetValue(v)
+	return nil
+}
+
+func (m *TextAreaModel) Reset() {
+	m.input.SetValue(m.initial)
+	m.err = nil
+}
+
+func (m TextAreaModel) Dirty() bool {
+	return m.input.Value() != m.initial
+}
+
+func (m *TextAreaModel) Focus() tea.Cmd {
+	if m.disabled {
+		return nil
+	}
+	return m.input.Focus()
+}
+
+func (m TextAreaModel) Disabled() bool {
+	return m.disabled
+}
+
+func (m *TextAreaModel) SetDisabled(disabled bool) {
+	m.disabled = disabled
+	if disabled {
+		m.input.Blur()
+	}
+}
+
+func (m *TextAreaModel) Validate() error {
+	m.err = nil
+	if m.validate != nil {
+		m.err = m.validate(m.Value())
+	}
+	return m.err
+}
+
+func (m TextAreaModel) Err() error {
+	return m.err
+}
+
+func (m TextAreaModel) Focused() bool {
+	return m.input.Focused()
+}
+
+func (m TextAreaModel) Id() string {
+	return m.id
+}
+
+func (m TextAreaModel) Value() any {
+	return m.input.Value()
+}
@@ -1,40 +1,81 @@
 package boba
 
 import (
+	"fmt"
+	"strings"
+	"unicode"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type TextInputModel struct {
-	theme  Theme
-	id     string
-	input  textinput.Model
-	noUp   bool
-	noDown bool
-	keys   KeyOpts
+	theme       Theme
+	id          string
+	label       string
+	description string
+	initial     string
+	input       textinput.Model
+	noUp        bool
+	noDown      bool
+	disabled    bool
+	echoMode    textinput.EchoMode
+	mask        []rune
+	validate    ValidateFunc
+	onKeystroke ValidateFunc
+	err         error
+	typingErr   bool // Whether the error is from the keystroke validation
+	keys        KeyOpts
 }
 
 type NewTextInputOptions struct {
 	NoDown      bool
 	NoUp        bool
 	Id          string
+	Label       string
+	Description string // Help text rendered beneath the input
 	Placeholder string
-	Theme       Theme
-	Keys        KeyOpts
+	Value       string // The initial value of the input, restored by Reset
+	Disabled    bool
+	Validate    ValidateFunc // Run when focus leaves the input, and on every change while invalid
+	OnKeystroke ValidateFunc // Run on every change, its error is shown while the user types
+
+	EchoMode      textinput.EchoMode // Hides the text of passwords, which the reveal key shows and hides
+	EchoCharacter rune               // Replaces each character of a password, defaults to *
+	CharLimit     int
+	Mask          string // Formats the text as it is typed, e.g. "99/99/9999". 9 is a digit, a is a letter and * is any character, others are inserted as is
+
+	Theme Theme
+	Keys  KeyOpts
 }
 
 // Wrapper around the textinput model from BubbleTea. Extended to handle focusing and
 // moving up/down to the nearest ComponentModel
 func NewTextInputModel(opts NewTextInputOptions, models ...textinput.Model) ComponentModel {
 	ti := TextInputModel{
-		input:  textinput.New(),
-		id:     opts.Id,
-		noUp:   opts.NoUp,
-		noDown: opts.NoDown,
-		theme:  opts.Theme,
-		keys:   opts.Keys,
+		input:       textinput.New(),
+		id:          opts.Id,
+		label:       opts.Label,
+		description: opts.Description,
+		initial:     opts.Value,
+		noUp:        opts.NoUp,
+		noDown:      opts.NoDown,
+		disabled:    opts.Disabled,
+		echoMode:    opts.EchoMode,
+		mask:        []rune(opts.Mask),
+		validate:    opts.Validate,
+		onKeystroke: opts.OnKeystroke,
+		theme:       opts.Theme,
+		keys:        opts.Keys,
 	}
 	ti.input.Placeholder = opts.Placeholder
+	ti.input.EchoMode = opts.EchoMode
+	if opts.EchoCharacter != 0 {
+		ti.input.EchoCharacter = opts.EchoCharacter
+	}
+	ti.input.CharLimit = opts.CharLimit
+	ti.initial = ti.format(opts.Value)
+	ti.input.SetValue(ti.initial)
 	return &ti
 }
 
@@ -43,9 +84,17 @@ func (m TextInputModel) Init() tea.Cmd {
 }
 
 func (m TextInputModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	if m.disabled {
+		return &m, nil
+	}
+
 	var cmds = []tea.Cmd{}
 
+	prev := m.input.Value()
 	m.input = UpdateSubmodel(m.input, msg, &cmds)
+	if m.input.Value() != prev {
+		m.changed()
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -53,38 +102,202 @@ func (m TextInputModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 		case m.keys.Up:
 			if m.Focused() && !m.noUp {
 				m.Blur()
+				m.Validate()
 				return &m, back(m.id)
 			}
 		case m.keys.Down:
 			if m.Focused() && !m.noDown {
 				m.Blur()
+				m.Validate()
 				return &m, next(m.id)
 			}
 		case m.keys.Back:
 			m.Blur()
 			return &m, nil
+		case m.keys.Reveal:
+			if m.Focused() && m.echoMode != textinput.EchoNormal {
+				m.reveal()
+			}
 		}
 	}
 
 	return &m, tea.Batch(cmds...)
 }
 
+// Formats the text with the mask after it is edited, keeping the cursor at the same
+// distance from the end of the text. The keystroke validation runs on every change, while
+// an error from Validate is only checked again until the user fixes the value
+func (m *TextInputModel) changed() {
+	if len(m.mask) > 0 {
+		value := m.input.Value()
+		fromEnd := len([]rune(value)) - m.input.Position()
+		formatted := m.format(value)
+		m.input.SetValue(formatted)
+		m.input.SetCursor(len([]rune(formatted)) - fromEnd)
+	}
+	switch {
+	case m.err != nil && !m.typingErr:
+		m.Validate()
+	case m.onKeystroke != nil:
+		m.err = m.onKeystroke(m.input.Value())
+		m.typingErr = m.err != nil
+	}
+}
+
+// Shows the hidden text of the input, or hides it again
+func (m *TextInputModel) reveal() {
+	if m.input.EchoMode == textinput.EchoNormal {
+		m.input.EchoMode = m.echoMode
+	} else {
+		m.input.EchoMode = textinput.EchoNormal
+	}
+}
+
+// Fits the text into the mask. Characters that do not fit the next slot of the mask are
+// dropped, and the other characters of the mask are inserted as the text reaches them
+func (m TextInputModel) format(value string) string {
+	if len(m.mask) == 0 {
+		return value
+	}
+
+	runes := []rune(value)
+	formatted := make([]rune, 0, len(m.mask))
+	i := 0
+	for _, slot := range m.mask {
+		if i == len(runes) {
+			break
+		}
+		if !maskSlot(slot) {
+			formatted = append(formatted, slot)
+			if runes[i] == slot { // The character was typed rather than inserted
+				i++
+			}
+			continue
+		}
+		for i < len(runes) && !fitsMask(slot, runes[i]) {
+			i++
+		}
+		if i == len(runes) {
+			break
+		}
+		formatted = append(formatted, runes[i])
+		i++
+	}
+	return string(formatted)
+}
+
+// Indicates whether the character of a mask is a slot for the text rather than inserted
+func maskSlot(slot rune) bool {
+	return slot == '9' || slot == 'a' || slot == '*'
+}
+
+func fitsMask(slot rune, r rune) bool {
+	switch slot {
+	case '9':
+		return unicode.IsDigit(r)
+	case 'a':
+		return unicode.IsLetter(r)
+	}
+	return true
+}
+
 func (m TextInputModel) View() string {
-	return rebuildCursor(m.input.View(), m.input.Focused(), m.theme)
+	base := strings.Builder{}
+	if m.label != "" {
+		base.WriteString(fmt.Sprintf("%s\n", m.theme.ColorCond(m.label, Primary, m.Focused())))
+	}
+	base.WriteString(m.fieldView() + "\n")
+	if m.description != "" {
+		base.WriteString(fmt.Sprintf("  %s\n", m.theme.Color(m.description, Neutral)))
+	}
+	if m.err != nil {
+		base.WriteString(fmt.Sprintf("  %s\n", m.theme.Color(m.err.Error(), Error)))
+	}
+	return base.String()
+}
+
+// Renders the input without its label or description, for use in a Form
+func (m TextInputModel) fieldView() string {
+	if m.disabled {
+		return m.theme.Color(m.input.View(), Neutral)
+	}
+	return strings.TrimSuffix(rebuildCursor(m.input.View(), m.input.Focused(), m.theme), "\n")
+}
+
+func (m TextInputModel) Label() string {
+	return m.label
+}
+
+func (m TextInputModel) Description() string {
+	return m.description
 }
 
 func (m *TextInputModel) Blur() {
 	m.input.Blur()
+	m.input.EchoMode = m.echoMode // Hides a revealed password
 }
 
 func (m *TextInputModel) Clear() {
 	m.input.SetValue("")
+	m.input.EchoMode = m.echoMode
+	m.err = nil
+}
+
+// Sets the text of the input, the value must be a string
+func (m *TextInputModel) SetValue(value any) error {
+	v, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("text input %q expects a string, got %T", m.id, value)
+	}
+	m.input.SetValue(m.format(v))
+	return nil
+}
+
+func (m *TextInputModel) Reset() {
+	m.input.SetValue(m.initial)
+	m.input.EchoMode = m.echoMode
+	m.err = nil
+}
+
+func (m TextInputModel) Dirty() bool {
+	return m.input.Value() != m.initial
 }
 
 func (m *TextInputModel) Focus() tea.Cmd {
+	if m.disabled {
+		return nil
+	}
 	return m.input.Focus()
 }
 
+func (m TextInputModel) Disabled() bool {
+	return m.disabled
+}
+
+func (m *TextInputModel) SetDisabled(disabled bool) {
+	m.disabled = disabled
+	if disabled {
+		m.input.Blur()
+	}
+}
+
+func (m *TextInputModel) Validate() error {
+	m.err = nil
+	if m.validate != nil {
+		m.err = m.validate(m.Value())
+	}
+	m.typingErr = false
+	if m.err == nil && m.onKeystroke != nil {
+		m.err = m.onKeystroke(m.input.Value())
+		m.typingErr = m.err != nil
+	}
+	return m.err
+}
+
+func (m TextInputModel) Err() error {
+	return m.err
+}
+
 func (m TextInputModel) Focused() bool {
 	return m.input.Focused()
 }
@@ -7,24 +7,32 @@ import (
 )
 
 type ToggleModel struct {
-	name    string
-	on      bool
-	label   string
-	focused bool
-	noUp    bool
-	noDown  bool
-	theme   Theme
-	keys    KeyOpts
+	name        string
+	on          bool
+	initial     bool
+	label       string
+	description string
+	focused     bool
+	noUp        bool
+	noDown      bool
+	disabled    bool
+	validate    ValidateFunc
+	err         error
+	theme       Theme
+	keys        KeyOpts
 }
 
 type NewToggleOptions struct {
-	Label  string
-	Name   string
-	NoDown bool
-	NoUp   bool
-	Theme  Theme
-	Keys   KeyOpts
-	On     bool
+	Label       string
+	Description string // Help text rendered beneath the toggle
+	Name        string
+	NoDown      bool
+	NoUp        bool
+	Theme       Theme
+	Keys        KeyOpts
+	On          bool
+	Disabled    bool
+	Validate    ValidateFunc // Run whenever the toggle changes
 }
 
 type SetToggleMsg struct{ On bool }
@@ -33,13 +41,17 @@ type SetToggleMsg struct{ On bool }
 // to be used in forms
 func NewToggleModel(opts NewToggleOptions) ComponentModel {
 	return &ToggleModel{
-		on:     opts.On,
-		label:  opts.Label,
-		name:   opts.Name,
-		noDown: opts.NoDown,
-		noUp:   opts.NoUp,
-		theme:  opts.Theme,
-		keys:   opts.Keys,
+		on:          opts.On,
+		initial:     opts.On,
+		label:       opts.Label,
+		description: opts.Description,
+		name:        opts.Name,
+		noDown:      opts.NoDown,
+		noUp:        opts.NoUp,
+		disabled:    opts.Disabled,
+		validate:    opts.Validate,
+		theme:       opts.Theme,
+		keys:        opts.Keys,
 	}
 }
 
@@ -48,6 +60,17 @@ func (m ToggleModel) Init() tea.Cmd {
 }
 
 func (m ToggleModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	if m.disabled {
+		return &m, nil
+	}
+	if msg, ok := msg.(tea.MouseMsg); ok { // Clicks are handled whether or not the toggle is focused
+		if clicked(msg) && msg.Y == 0 { // The toggle is rendered on the first line
+			m.on = !m.on
+			m.Validate()
+			return &m, m.changeToggle
+		}
+		return &m, nil
+	}
 	if !m.Focused() {
 		return &m, nil
 	}
@@ -58,6 +81,7 @@ func (m ToggleModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 		switch msg.String() {
 		case m.keys.Toggle:
 			m.on = !m.on
+			m.Validate()
 			return &m, m.changeToggle
 		case m.keys.Up:
 			if m.Focused() && !m.noUp {
@@ -82,15 +106,44 @@ func (m ToggleModel) changeToggle() tea.Msg {
 }
 
 func (m ToggleModel) View() string {
-	base := fmt.Sprintf("%s %s: ", m.theme.ColorCond(">", Primary, m.Focused()), m.label)
+	label := m.theme.ColorCond(m.label, Neutral, m.disabled)
+	base := fmt.Sprintf("%s %s: %s\n", m.theme.ColorCond(">", Primary, m.Focused()), label, m.valueView())
+	if m.description != "" {
+		base += fmt.Sprintf("  %s\n", m.theme.Color(m.description, Neutral))
+	}
+	if m.err != nil {
+		base += fmt.Sprintf("  %s\n", m.theme.Color(m.err.Error(), Error))
+	}
+	return base
+}
+
+// Renders the toggle without its label or description, for use in a Form
+func (m ToggleModel) fieldView() string {
+	return fmt.Sprintf("%s %s", m.theme.ColorCond(">", Primary, m.Focused()), m.valueView())
+}
+
+func (m ToggleModel) valueView() string {
+	value, color := "No", Neutral
 	if m.on {
-		base += m.theme.Color("Yes", Success)
-	} else {
-		base += m.theme.Color("No", Neutral)
+		value, color = "Yes", Success
+	}
+	if m.disabled {
+		color = Neutral
 	}
+	return m.theme.Color(value, color)
+}
 
-	base += "\n"
-	return base
+// Returns the region taken up by the view of the toggle
+func (m ToggleModel) Region() Region {
+	return viewRegion(m.View(), 0, 0)
+}
+
+func (m ToggleModel) Label() string {
+	return m.label
+}
+
+func (m ToggleModel) Description() string {
+	return m.description
 }
 
 func (m ToggleModel) Focused() bool {
@@ -98,10 +151,33 @@ func (m ToggleModel) Focused() bool {
 }
 
 func (m *ToggleModel) Focus() tea.Cmd {
-	m.focused = true
+	m.focused = !m.disabled
 	return nil
 }
 
+func (m ToggleModel) Disabled() bool {
+	return m.disabled
+}
+
+func (m *ToggleModel) SetDisabled(disabled bool) {
+	m.disabled = disabled
+	if disabled {
+		m.focused = false
+	}
+}
+
+func (m *ToggleModel) Validate() error {
+	m.err = nil
+	if m.validate != nil {
+		m.err = m.validate(m.Value())
+	}
+	return m.err
+}
+
+func (m ToggleModel) Err() error {
+	return m.err
+}
+
 func (m *ToggleModel) Blur() {
 	m.focused = false
 }
@@ -113,6 +189,27 @@ func (m ToggleModel) Id() string {
 func (m *ToggleModel) Clear() {
 	m.focused = false
 	m.on = false
+	m.err = nil
+}
+
+// Sets the state of the toggle, the value must be a bool
+func (m *ToggleModel) SetValue(value any) error {
+	v, ok := value.(bool)
+	if !ok {
+		return fmt.Errorf("toggle %q expects a bool, got %T", m.name, value)
+	}
+	m.on = v
+	return nil
+}
+
+func (m *ToggleModel) Reset() {
+	m.focused = false
+	m.on = m.initial
+	m.err = nil
+}
+
+func (m ToggleModel) Dirty() bool {
+	return m.on != m.initial
 }
 
 func (m ToggleModel) Value() any {
@@ -0,0 +1,531 @@
+package boba
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A node in the TreeSelectorModel, which can contain further nodes
+type TreeNode struct {
+	Label    string     `json:"label"`
+	Value    string     `json:"value"` // Uniquely identifies the node within the tree
+	Children []TreeNode `json:"children"`
+	Lazy     bool       `json:"lazy"` // The children are loaded when the node is first expanded
+	Expanded bool       `json:"expanded"`
+	Selected bool       `json:"selected"`
+	Disabled bool       `json:"disabled"`
+}
+
+// Used to set the nodes of the tree. When a Name is provided only the tree with that name
+// takes the nodes
+type TreeNodesMsg struct {
+	Name  string
+	Nodes []TreeNode
+}
+
+// Triggered when a node is chosen in a single-select tree
+type TreeSelectMsg struct {
+	Name string
+	Node TreeNode
+}
+
+// Triggered when a node is toggled in a multi-select tree, which toggles its children too
+type TreeToggleMsg struct {
+	Name string
+	Node TreeNode
+}
+
+// Triggered by the select key in a multi-select tree, contains the values of the
+// selected nodes
+type TreeSubmitMsg struct {
+	Name   string
+	Values []string
+}
+
+// Contains the children loaded for a lazy node
+type treeChildrenMsg struct {
+	name     string
+	value    string
+	children []TreeNode
+	err      error
+}
+
+// The state of a checkbox in a multi-select tree. Parents are partially checked when
+// only some of their children are selected
+type checkState int
+
+const (
+	unchecked checkState = iota
+	partial
+	checked
+)
+
+// A visible row of the tree
+type treeRow struct {
+	path    []int // Indexes of the node and of its ancestors, starting from the roots
+	depth   int
+	matches []int // Positions of the runes matched by the filter in the label
+}
+
+type TreeSelectorModel struct {
+	cursor       int
+	offset       int
+	nodes        []TreeNode
+	rows         []treeRow
+	query        string // The filter text the rows were last built with
+	stale        bool   // Whether the nodes or their expansion changed since the rows were built
+	filter       textinput.Model
+	filterHidden bool
+	matcher      Matcher
+	keys         KeyOpts
+	theme        Theme
+	name         string
+	maxHeight    func() int
+	multi        bool
+	selected     string
+	loadChildren func(node TreeNode) ([]TreeNode, error)
+	loading      map[string]bool // Values of the nodes whose children are being loaded
+}
+
+type NewTreeSelectorModelOpts struct {
+	Filter       FilterOpts
+	Nodes        []TreeNode
+	Theme        Theme
+	Name         string
+	MaxHeight    func() int
+	Keys         KeyOpts
+	Multi        bool                                    // Allows for several nodes to be checked, checking a node checks its children
+	LoadChildren func(node TreeNode) ([]TreeNode, error) // Loads the children of lazy nodes
+}
+
+// Allows for the selection of nodes in a hierarchy, such as files in directories. Nodes
+// are expanded and collapsed with the collapse key. The selection triggers the
+// TreeSelectMsg, or the TreeToggleMsg when multiple nodes can be selected
+func NewTreeSelectorModel(opts NewTreeSelectorModelOpts) TreeSelectorModel {
+	m := TreeSelectorModel{
+		theme:        opts.Theme,
+		name:         opts.Name,
+		maxHeight:    opts.MaxHeight,
+		keys:         opts.Keys,
+		matcher:      opts.Filter.Matcher,
+		filterHidden: opts.Filter.Hidden,
+		multi:        opts.Multi,
+		loadChildren: opts.LoadChildren,
+		loading:      make(map[string]bool),
+	}
+
+	if !opts.Filter.Hidden {
+		ti := textinput.New()
+		ti.Placeholder = opts.Filter.Placeholder
+		m.filter = ti
+	}
+
+	m.setNodes(opts.Nodes)
+	m.refresh()
+	return m
+}
+
+func (m TreeSelectorModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m TreeSelectorModel) Update(msg tea.Msg) (TreeSelectorModel, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	m.filter = UpdateSubmodel(m.filter, msg, &cmds)
+
+	switch msg := msg.(type) {
+	case TreeNodesMsg:
+		if msg.Name == "" || msg.Name == m.name {
+			m.setNodes(msg.Nodes)
+		}
+	case treeChildrenMsg:
+		if msg.name == m.name {
+			cmds = append(cmds, m.setChildren(msg))
+		}
+	case tea.KeyMsg:
+		if m.filter.Focused() {
+			switch msg.String() {
+			case m.keys.Select, m.keys.Back:
+				m.filter.Blur()
+			}
+			break
+		}
+
+		switch msg.String() {
+		case m.keys.Down:
+			m.cursor = moveCursor(m.cursor, 1, len(m.rows))
+		case m.keys.Up:
+			m.cursor = moveCursor(m.cursor, -1, len(m.rows))
+		case m.keys.PageDown:
+			m.cursor = moveCursor(m.cursor, m.pageSize(), len(m.rows))
+		case m.keys.PageUp:
+			m.cursor = moveCursor(m.cursor, -m.pageSize(), len(m.rows))
+		case m.keys.Home:
+			m.cursor = 0
+		case m.keys.End:
+			m.cursor = max(len(m.rows)-1, 0)
+		case m.keys.Collapse:
+			cmds = append(cmds, m.toggleExpanded())
+		case m.keys.Toggle:
+			if m.multi {
+				cmds = append(cmds, m.toggle())
+			}
+		case m.keys.Select:
+			if m.multi {
+				cmds = append(cmds, m.submit)
+			} else {
+				cmds = append(cmds, m.selectNode())
+			}
+		case m.keys.Filter:
+			if !m.filterHidden {
+				cmds = append(cmds, textinput.Blink)
+				m.filter.Focus()
+			}
+		case m.keys.Back:
+			return m, back(m.name)
+		}
+	case tea.MouseMsg:
+		if direction, ok := scrolled(msg); ok && !m.filter.Focused() {
+			step := 1
+			if direction == Up {
+				step = -1
+			}
+			m.cursor = moveCursor(m.cursor, step, len(m.rows))
+		}
+	}
+
+	// Reset the cursor when someone filters the tree
+	if m.filter.Focused() {
+		m.cursor = 0
+	}
+
+	if m.stale || m.filter.Value() != m.query {
+		m.refresh()
+	}
+	m.cursor = min(m.cursor, max(len(m.rows)-1, 0))
+	m.offset, _ = m.window()
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m TreeSelectorModel) View() string {
+	base := strings.Builder{}
+	if !m.filterHidden {
+		base.WriteString(rebuildCursor(m.filter.View(), m.filter.Focused(), m.theme))
+	}
+	if len(m.rows) == 0 {
+		base.WriteString("No options found \n")
+		return base.String()
+	}
+
+	start, end := m.window()
+	for i := start; i < end; i++ {
+		row := m.rows[i]
+		node := m.node(row.path)
+
+		if i == m.cursor {
+			base.WriteString(m.theme.ColorCond("> ", Primary, !m.filter.Focused()))
+		} else {
+			base.WriteString("  ")
+		}
+		base.WriteString(strings.Repeat("  ", row.depth))
+
+		switch {
+		case !hasChildren(*node):
+			base.WriteString("  ")
+		case node.Expanded || m.filter.Value() != "":
+			base.WriteString(m.theme.Color("▾ ", Secondary))
+		default:
+			base.WriteString(m.theme.Color("▸ ", Secondary))
+		}
+
+		var color ColorType
+		if node.Disabled {
+			color = Neutral
+		} else if !m.multi && m.selected != "" && node.Value == m.selected {
+			color = Success
+		}
+
+		if m.multi {
+			base.WriteString(m.theme.Color(checkbox(nodeState(*node)), color) + " ")
+		}
+		base.WriteString(highlight(node.Label, row.matches, m.theme, color))
+		if m.loading[node.Value] {
+			base.WriteString(m.theme.Color("  Loading...", Neutral))
+		}
+		base.WriteString("\n")
+	}
+
+	if start > 0 || end < len(m.rows) {
+		base.WriteString(scrollIndicator(start, end, len(m.rows), m.theme))
+	}
+
+	return base.String()
+}
+
+// Returns the value of the selected node, or the values of the checked nodes when
+// multiple nodes can be selected
+func (m TreeSelectorModel) Value() any {
+	if !m.multi {
+		return m.selected
+	}
+	var values []string
+	walkNodes(m.nodes, func(node *TreeNode) {
+		if nodeState(*node) == checked {
+			values = append(values, node.Value)
+		}
+	})
+	return values
+}
+
+// Rebuilds the visible rows from the expanded nodes. While filtering, the nodes that match
+// are shown along with their ancestors, whether or not the ancestors are expanded. Only
+// called once the filter text, the nodes or their expansion change
+func (m *TreeSelectorModel) refresh() {
+	m.query, m.stale = m.filter.Value(), false
+	m.rows = treeRows(m.nodes, nil, 0, newMatchText(m.query), resolveMatcher(m.matcher))
+}
+
+func treeRows(nodes []TreeNode, parent []int, depth int, query matchText, match func(matchText, matchText) (int, []int, bool)) []treeRow {
+	var rows []treeRow
+	for i, node := range nodes {
+		row := treeRow{path: append(slices.Clone(parent), i), depth: depth}
+		if query.text == "" {
+			rows = append(rows, row)
+			if node.Expanded {
+				rows = append(rows, treeRows(node.Children, row.path, depth+1, query, match)...)
+			}
+			continue
+		}
+
+		_, matches, ok := match(query, newMatchText(node.Label))
+		children := treeRows(node.Children, row.path, depth+1, query, match)
+		if ok || len(children) > 0 {
+			row.matches = matches
+			rows = append(rows, row)
+			rows = append(rows, children...)
+		}
+	}
+	return rows
+}
+
+// Sets the nodes of the tree. The children of selected nodes are selected as well when
+// multiple nodes can be selected, otherwise the first selected node is chosen. The nodes
+// are copied, since their selection and expansion change as the tree is used
+func (m *TreeSelectorModel) setNodes(nodes []TreeNode) {
+	m.nodes = cloneNodes(nodes)
+	m.stale = true
+	walkNodes(m.nodes, func(node *TreeNode) {
+		switch {
+		case !node.Selected:
+		case m.multi:
+			setSelected(node, true)
+		case m.selected == "":
+			m.selected = node.Value
+		}
+	})
+}
+
+// Returns a deep copy of the nodes along with their children
+func cloneNodes(nodes []TreeNode) []TreeNode {
+	if nodes == nil {
+		return nil
+	}
+	clone := make([]TreeNode, len(nodes))
+	for i, node := range nodes {
+		node.Children = cloneNodes(node.Children)
+		clone[i] = node
+	}
+	return clone
+}
+
+// Returns the node at the path, which points into the nodes of the model
+func (m TreeSelectorModel) node(path []int) *TreeNode {
+	var node *TreeNode
+	nodes := m.nodes
+	for _, i := range path {
+		node = &nodes[i]
+		nodes = node.Children
+	}
+	return node
+}
+
+// Returns the node under the cursor and its path, if there is one
+func (m TreeSelectorModel) current() (*TreeNode, []int, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil, nil, false
+	}
+	path := m.rows[m.cursor].path
+	return m.node(path), path, true
+}
+
+// Expands or collapses the node under the cursor. The children of a lazy node are loaded
+// the first time it is expanded
+func (m *TreeSelectorModel) toggleExpanded() tea.Cmd {
+	node, _, ok := m.current()
+	if !ok || !hasChildren(*node) || m.filter.Value() != "" {
+		return nil
+	}
+
+	node.Expanded = !node.Expanded
+	m.stale = true
+	if !node.Expanded || !node.Lazy || len(node.Children) > 0 || m.loadChildren == nil || m.loading[node.Value] {
+		return nil
+	}
+
+	m.loading[node.Value] = true
+	name, load, parent := m.name, m.loadChildren, *node
+	return func() tea.Msg {
+		children, err := load(parent)
+		return treeChildrenMsg{name: name, value: parent.Value, children: children, err: err}
+	}
+}
+
+// Places the loaded children beneath their parent, they are checked if the parent is
+func (m *TreeSelectorModel) setChildren(msg treeChildrenMsg) tea.Cmd {
+	delete(m.loading, msg.value)
+	var parent *TreeNode
+	walkNodes(m.nodes, func(node *TreeNode) {
+		if node.Value == msg.value {
+			parent = node
+		}
+	})
+	if parent == nil {
+		return nil
+	}
+
+	m.stale = true
+	if msg.err != nil {
+		parent.Expanded = false
+		return func() tea.Msg {
+			return ErrMsg{msg.err}
+		}
+	}
+
+	parent.Children = cloneNodes(msg.children)
+	if parent.Selected {
+		setSelected(parent, true)
+	}
+	return nil
+}
+
+// Chooses the node under the cursor, triggers the TreeSelectMsg for use in other models
+func (m *TreeSelectorModel) selectNode() tea.Cmd {
+	node, _, ok := m.current()
+	if !ok || node.Disabled {
+		return nil
+	}
+	m.selected = node.Value
+	msg := TreeSelectMsg{Name: m.name, Node: *node}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Checks or unchecks the node under the cursor along with its children, and updates its
+// ancestors. Triggers the TreeToggleMsg for use in other models
+func (m *TreeSelectorModel) toggle() tea.Cmd {
+	node, path, ok := m.current()
+	if !ok || node.Disabled {
+		return nil
+	}
+
+	setSelected(node, nodeState(*node) != checked)
+	for i := len(path) - 1; i > 0; i-- { // Parents are selected once all of their children are
+		parent := m.node(path[:i])
+		parent.Selected = nodeState(*parent) == checked
+	}
+
+	msg := TreeToggleMsg{Name: m.name, Node: *node}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+func (m *TreeSelectorModel) submit() tea.Msg {
+	values, _ := m.Value().([]string)
+	return TreeSubmitMsg{Name: m.name, Values: values}
+}
+
+// Returns the number of lines available to the rows
+func (m TreeSelectorModel) listHeight() int {
+	reserved := 0
+	if !m.filterHidden {
+		reserved++
+	}
+	return listHeight(m.maxHeight, reserved, len(m.rows))
+}
+
+// Returns the bounds of the rows that are displayed in the scrolled window
+func (m TreeSelectorModel) window() (int, int) {
+	return scrollWindow(m.offset, m.cursor, len(m.rows), m.listHeight(), func(int, int) int { return 1 })
+}
+
+// Returns the number of rows that are displayed at once, used for paging
+func (m TreeSelectorModel) pageSize() int {
+	start, end := m.window()
+	return max(end-start, 1)
+}
+
+// Indicates whether the node has children, including ones that have yet to be loaded
+func hasChildren(node TreeNode) bool {
+	return len(node.Children) > 0 || node.Lazy
+}
+
+// Selects or unselects the node along with its descendants, skipping disabled nodes
+func setSelected(node *TreeNode, selected bool) {
+	node.Selected = selected
+	for i := range node.Children {
+		if !node.Children[i].Disabled {
+			setSelected(&node.Children[i], selected)
+		}
+	}
+}
+
+// Returns the state of the node's checkbox, which is derived from its children when it
+// has any. Disabled children are ignored
+func nodeState(node TreeNode) checkState {
+	all, some, counted := true, false, false
+	for _, child := range node.Children {
+		if child.Disabled {
+			continue
+		}
+		state := nodeState(child)
+		all = all && state == checked
+		some = some || state != unchecked
+		counted = true
+	}
+
+	switch {
+	case !counted && node.Selected, counted && all:
+		return checked
+	case some:
+		return partial
+	default:
+		return unchecked
+	}
+}
+
+// Renders the checkbox for the state
+func checkbox(state checkState) string {
+	switch state {
+	case checked:
+		return "[x]"
+	case partial:
+		return "[-]"
+	default:
+		return "[ ]"
+	}
+}
+
+// Calls the function for every loaded node, parents before their children
+func walkNodes(nodes []TreeNode, fn func(node *TreeNode)) {
+	for i := range nodes {
+		fn(&nodes[i])
+		walkNodes(nodes[i].Children, fn)
+	}
+}
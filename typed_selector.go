@@ -0,0 +1,295 @@
+package boba
+
+import (
+	"fmt"
+	"maps"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// An option in the TypedSelectorModel, which carries a value of any type
+type TypedSelectorOption[T any] struct {
+	Label       string
+	Value       T
+	Key         string // Uniquely identifies the option, defaults to the formatted value
+	Disabled    bool
+	Description string
+	Keywords    []string
+	Icon        string
+	Group       string
+}
+
+// Used to set the options in the TypedSelectorModel
+type TypedSelectorOptionsMsg[T any] struct {
+	Name    string // Only the selector with the name takes the options, when provided
+	Options []TypedSelectorOption[T]
+}
+
+// Triggered in place of the SelectMsg when an option in a TypedSelectorModel is chosen
+type TypedSelectMsg[T any] struct {
+	Option TypedSelectorOption[T]
+}
+
+// Triggered in place of the SelectorCreateMsg when the filter text is added as a new option
+// to a TypedSelectorModel
+type TypedSelectorCreateMsg[T any] struct {
+	Name   string
+	Option TypedSelectorOption[T]
+}
+
+// Wraps the SelectorModel so that its options carry typed values rather than strings
+type TypedSelectorModel[T any] struct {
+	SelectorModel
+	options map[string]TypedSelectorOption[T]
+	create  func(text string) T
+}
+
+type NewTypedSelectorModelOpts[T any] struct {
+	NewSelectorModelOpts
+	Options []TypedSelectorOption[T]
+	Create  func(text string) T // Builds the value of an option created from the filter text, Creatable has no effect without it
+}
+
+// Allows for the selection of a single typed value among a list of options.
+// The selection triggers the TypedSelectMsg which can be handled by other models.
+func NewTypedSelectorModel[T any](opts NewTypedSelectorModelOpts[T]) TypedSelectorModel[T] {
+	m := TypedSelectorModel[T]{create: opts.Create}
+	opts.NewSelectorModelOpts.Options = m.setOptions(opts.Options)
+	opts.Creatable = opts.Creatable && opts.Create != nil // Created options need a typed value
+	m.SelectorModel = NewSelectorModel(opts.NewSelectorModelOpts)
+	return m
+}
+
+func (m TypedSelectorModel[T]) Update(msg tea.Msg) (TypedSelectorModel[T], tea.Cmd) {
+	if options, ok := msg.(TypedSelectorOptionsMsg[T]); ok && (options.Name == "" || options.Name == m.name) {
+		msg = SelectorOptionsMsg{Name: options.Name, Options: m.setOptions(options.Options)}
+	}
+
+	var cmd tea.Cmd
+	count := len(m.SelectorModel.options)
+	m.SelectorModel, cmd = m.SelectorModel.Update(msg)
+	if m.create != nil && len(m.SelectorModel.options) == count+1 { // The filter text was added as an option
+		opt := m.SelectorModel.options[count]
+		if _, ok := m.options[opt.Value]; !ok {
+			m.options = maps.Clone(m.options) // The map is shared with the commands of earlier updates
+			m.options[opt.Value] = TypedSelectorOption[T]{Label: opt.Label, Value: m.create(opt.Value), Key: opt.Value}
+		}
+	}
+	return m, mapCmd(cmd, m.convert)
+}
+
+// Returns the typed value of the selected option
+func (m TypedSelectorModel[T]) Selected() (T, bool) {
+	opt, ok := m.options[m.selected]
+	return opt.Value, ok
+}
+
+// Stores the typed options by their keys and returns the equivalent string options
+func (m *TypedSelectorModel[T]) setOptions(options []TypedSelectorOption[T]) SelectorOptions {
+	m.options = make(map[string]TypedSelectorOption[T], len(options))
+	result := make(SelectorOptions, len(options))
+	for i, opt := range options {
+		if opt.Key == "" {
+			opt.Key = fmt.Sprint(opt.Value)
+		}
+		m.options[opt.Key] = opt
+		result[i] = SelectorOption{
+			Label:       opt.Label,
+			Value:       opt.Key,
+			Disabled:    opt.Disabled,
+			Description: opt.Description,
+			Keywords:    opt.Keywords,
+			Icon:        opt.Icon,
+			Group:       opt.Group,
+		}
+	}
+	return result
+}
+
+// Replaces the messages of the SelectorModel with their typed equivalents
+func (m TypedSelectorModel[T]) convert(msg tea.Msg) tea.Msg {
+	switch msg := msg.(type) {
+	case SelectMsg:
+		return TypedSelectMsg[T]{Option: m.options[msg.Option.Value]}
+	case SelectorCreateMsg:
+		return TypedSelectorCreateMsg[T]{Name: msg.Name, Option: m.options[msg.Option.Value]}
+	}
+	return msg
+}
+
+// An option in the TypedMultiSelectorModel, which carries a value of any type
+type TypedMultiSelectorOption[T any] struct {
+	Label       string
+	Value       T
+	Key         string // Uniquely identifies the option, defaults to the formatted value
+	Selected    bool
+	Disabled    bool
+	Description string
+	Keywords    []string
+	Icon        string
+	Group       string
+}
+
+// Used to set the options in the TypedMultiSelectorModel
+type TypedMultiSelectorOptionsMsg[T any] struct {
+	Name    string // Only the multi-selector with the name takes the options, when provided
+	Options []TypedMultiSelectorOption[T]
+}
+
+// Triggered in place of the MultiSelectorOptionMsg when an option in a
+// TypedMultiSelectorModel is toggled
+type TypedMultiSelectorOptionMsg[T any] struct {
+	Option TypedMultiSelectorOption[T]
+}
+
+// Triggered in place of the MultiSelectorBulkMsg when a bulk action changes the selection
+// of a TypedMultiSelectorModel
+type TypedMultiSelectorBulkMsg[T any] struct {
+	Name    string
+	Options []TypedMultiSelectorOption[T]
+}
+
+// Triggered in place of the MultiSelectorSubmitMsg when the selection of a
+// TypedMultiSelectorModel is submitted
+type TypedMultiSelectorSubmitMsg[T any] struct {
+	Name    string
+	Options []TypedMultiSelectorOption[T]
+}
+
+// Triggered in place of the MultiSelectorReorderMsg when the selection of an ordered
+// TypedMultiSelectorModel is reordered
+type TypedMultiSelectorReorderMsg[T any] struct {
+	Name    string
+	Options []TypedMultiSelectorOption[T]
+}
+
+// Triggered in place of the MultiSelectorCreateMsg when the filter text is added as a new
+// option to a TypedMultiSelectorModel
+type TypedMultiSelectorCreateMsg[T any] struct {
+	Name   string
+	Option TypedMultiSelectorOption[T]
+}
+
+// Wraps the MultiSelectorModel so that its options carry typed values rather than strings
+type TypedMultiSelectorModel[T any] struct {
+	MultiSelectorModel
+	options map[string]TypedMultiSelectorOption[T]
+	create  func(text string) T
+}
+
+type NewTypedMultiSelectorModelOpts[T any] struct {
+	NewMultiSelectorModelOpts
+	Options []TypedMultiSelectorOption[T]
+	Create  func(text string) T // Builds the value of an option created from the filter text, Creatable has no effect without it
+}
+
+// Allows for the toggling of multiple typed values in a list. Toggling an option
+// triggers the TypedMultiSelectorOptionMsg which can be handled by other models.
+func NewTypedMultiSelectorModel[T any](opts NewTypedMultiSelectorModelOpts[T]) TypedMultiSelectorModel[T] {
+	m := TypedMultiSelectorModel[T]{create: opts.Create}
+	opts.NewMultiSelectorModelOpts.Options = m.setOptions(opts.Options)
+	opts.Creatable = opts.Creatable && opts.Create != nil // Created options need a typed value
+	m.MultiSelectorModel = NewMultiSelectorModel(opts.NewMultiSelectorModelOpts)
+	return m
+}
+
+func (m TypedMultiSelectorModel[T]) Update(msg tea.Msg) (TypedMultiSelectorModel[T], tea.Cmd) {
+	if options, ok := msg.(TypedMultiSelectorOptionsMsg[T]); ok && (options.Name == "" || options.Name == m.name) {
+		msg = MultiSelectorOptionsMsg{Name: options.Name, Options: m.setOptions(options.Options)}
+	}
+
+	var cmd tea.Cmd
+	count := len(m.MultiSelectorModel.options)
+	m.MultiSelectorModel, cmd = m.MultiSelectorModel.Update(msg)
+	if m.create != nil && len(m.MultiSelectorModel.options) == count+1 { // The filter text was added as an option
+		opt := m.MultiSelectorModel.options[count]
+		if _, ok := m.options[opt.Value]; !ok {
+			m.options = maps.Clone(m.options) // The map is shared with the commands of earlier updates
+			m.options[opt.Value] = TypedMultiSelectorOption[T]{Label: opt.Label, Value: m.create(opt.Value), Key: opt.Value}
+		}
+	}
+	return m, mapCmd(cmd, m.convert)
+}
+
+// Returns the typed values of the selected options, in the order of the selection when ordered
+func (m TypedMultiSelectorModel[T]) Selected() []T {
+	var values []T
+	for _, opt := range m.Selection() {
+		values = append(values, m.options[opt.Value].Value)
+	}
+	return values
+}
+
+// Stores the typed options by their keys and returns the equivalent string options
+func (m *TypedMultiSelectorModel[T]) setOptions(options []TypedMultiSelectorOption[T]) MultiSelectorOptions {
+	m.options = make(map[string]TypedMultiSelectorOption[T], len(options))
+	result := make(MultiSelectorOptions, len(options))
+	for i, opt := range options {
+		if opt.Key == "" {
+			opt.Key = fmt.Sprint(opt.Value)
+		}
+		m.options[opt.Key] = opt
+		result[i] = MultiSelectorOption{
+			Label:       opt.Label,
+			Value:       opt.Key,
+			Selected:    opt.Selected,
+			Disabled:    opt.Disabled,
+			Description: opt.Description,
+			Keywords:    opt.Keywords,
+			Icon:        opt.Icon,
+			Group:       opt.Group,
+		}
+	}
+	return result
+}
+
+// Replaces the messages of the MultiSelectorModel with their typed equivalents
+func (m TypedMultiSelectorModel[T]) convert(msg tea.Msg) tea.Msg {
+	switch msg := msg.(type) {
+	case MultiSelectorOptionMsg:
+		return TypedMultiSelectorOptionMsg[T]{Option: m.typed(msg.Option)}
+	case MultiSelectorBulkMsg:
+		return TypedMultiSelectorBulkMsg[T]{Name: msg.Name, Options: m.typedAll(msg.Options)}
+	case MultiSelectorCreateMsg:
+		return TypedMultiSelectorCreateMsg[T]{Name: msg.Name, Option: m.typed(msg.Option)}
+	case MultiSelectorSubmitMsg:
+		return TypedMultiSelectorSubmitMsg[T]{Name: msg.Name, Options: m.typedAll(msg.Options)}
+	case MultiSelectorReorderMsg:
+		return TypedMultiSelectorReorderMsg[T]{Name: msg.Name, Options: m.typedAll(msg.Options)}
+	}
+	return msg
+}
+
+// Returns the typed equivalent of the option, with its current selection
+func (m TypedMultiSelectorModel[T]) typed(option MultiSelectorOption) TypedMultiSelectorOption[T] {
+	opt := m.options[option.Value]
+	opt.Selected = option.Selected
+	return opt
+}
+
+func (m TypedMultiSelectorModel[T]) typedAll(options []MultiSelectorOption) []TypedMultiSelectorOption[T] {
+	typed := make([]TypedMultiSelectorOption[T], len(options))
+	for i, opt := range options {
+		typed[i] = m.typed(opt)
+	}
+	return typed
+}
+
+// Wraps the command so that the message it returns is passed through the mapping
+// function, including the messages of batched commands
+func mapCmd(cmd tea.Cmd, fn func(tea.Msg) tea.Msg) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			mapped := make(tea.BatchMsg, len(batch))
+			for i, c := range batch {
+				mapped[i] = mapCmd(c, fn)
+			}
+			return mapped
+		}
+		return fn(msg)
+	}
+}
@@ -0,0 +1,185 @@
+package boba
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A single page of the WizardModel
+type WizardStep struct {
+	Title    string
+	Form     Form
+	Validate func(values map[string]any) error // Called with the step's values before advancing
+}
+
+type WizardModel struct {
+	name         string
+	steps        []WizardStep
+	step         int
+	err          error
+	theme        Theme
+	formViewOpts FormViewOpts
+}
+
+type NewWizardModelOpts struct {
+	Name     string
+	Steps    []WizardStep
+	Theme    Theme
+	FormView FormViewOpts // Used to render the form of each step
+}
+
+// Groups several forms into consecutive steps. Moving past the last component of a step
+// validates it and advances to the next one, and moving before the first component
+// returns to the previous step with its values intact. The WizardModel is started with the
+// StartMsg, and triggers the WizardSubmitMsg once the final step is completed.
+func NewWizardModel(opts NewWizardModelOpts) WizardModel {
+	if opts.FormView.Theme == nil {
+		opts.FormView.Theme = opts.Theme
+	}
+	return WizardModel{
+		name:         opts.Name,
+		steps:        opts.Steps,
+		theme:        opts.Theme,
+		formViewOpts: opts.FormView,
+	}
+}
+
+// Contains the combined values of every step in the wizard, keyed by component Id
+type WizardSubmitMsg struct {
+	Name   string
+	Values map[string]any
+}
+
+func (m WizardModel) Init() tea.Cmd {
+	return nil
+}
+
+// The lines rendered above the form of each step, the step count and a blank line
+const wizardHeaderHeight = 2
+
+func (m WizardModel) Update(msg tea.Msg) (WizardModel, tea.Cmd) {
+	if len(m.steps) == 0 {
+		return m, nil
+	}
+
+	form := m.steps[m.step].Form
+
+	switch msg := msg.(type) {
+	case StartMsg:
+		cmd := m.start()
+		return m, cmd
+	case ComponentBackMsg:
+		if !form.isFirst(msg.ComponentName) {
+			break
+		}
+		m.err = nil
+		if m.step == 0 {
+			return m, back(m.name) // Hand focus off to whatever contains the wizard
+		}
+		m.step--
+		prev := m.steps[m.step].Form
+		return m, prev.focusAt(prev.nearest(len(prev)-1, Up))
+	case ComponentNextMsg:
+		if !form.isLast(msg.ComponentName) {
+			break
+		}
+		m.err = nil
+		if form.Validate() != nil { // Field errors are rendered beneath each field
+			return m, form.focusAt(form.nearest(len(form)-1, Up))
+		}
+		if err := m.validate(); err != nil {
+			m.err = err
+			return m, form.focusAt(form.nearest(len(form)-1, Up))
+		}
+		if m.step == len(m.steps)-1 {
+			return m, m.submit
+		}
+		m.step++
+		return m, m.steps[m.step].Form.focusAt(m.steps[m.step].Form.nearest(0, Down))
+	case tea.MouseMsg: // The form is rendered beneath the header with the wizard's view options
+		var cmd tea.Cmd
+		m.steps[m.step].Form, cmd = form.Mouse(Region{Y: wizardHeaderHeight}.Translate(msg), m.formViewOpts)
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.steps[m.step].Form, cmd = form.Update(msg) // Only the current step receives messages
+	return m, cmd
+}
+
+func (m WizardModel) View() string {
+	if len(m.steps) == 0 {
+		return ""
+	}
+
+	step := m.steps[m.step]
+	base := strings.Builder{}
+	base.WriteString(m.theme.Color(fmt.Sprintf("Step %d of %d", m.step+1, len(m.steps)), Neutral))
+	if step.Title != "" {
+		base.WriteString(fmt.Sprintf(" %s", m.theme.Color(step.Title, Primary)))
+	}
+	base.WriteString("\n\n")
+
+	base.WriteString(step.Form.Render(m.formViewOpts))
+
+	if m.err != nil {
+		base.WriteString(fmt.Sprintf("\n%s\n", m.theme.Color(m.err.Error(), Error)))
+	}
+
+	return base.String()
+}
+
+// Returns the index of the current step
+func (m WizardModel) Step() int {
+	return m.step
+}
+
+// Returns the combined values of every step
+func (m WizardModel) Values() map[string]any {
+	values := make(map[string]any)
+	for _, s := range m.steps {
+		for k, v := range s.Form.Values() {
+			values[k] = v
+		}
+	}
+	return values
+}
+
+// Indicates whether any step has unsaved changes
+func (m WizardModel) Dirty() bool {
+	for _, s := range m.steps {
+		if s.Form.Dirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Resets every step and focuses the first component of the first step
+func (m *WizardModel) start() tea.Cmd {
+	m.step = 0
+	m.err = nil
+	for _, s := range m.steps[1:] {
+		s.Form.Reset()
+		s.Form.blur()
+	}
+	return m.steps[0].Form.focus()
+}
+
+// Runs the validation function of the current step, if one is provided
+func (m WizardModel) validate() error {
+	step := m.steps[m.step]
+	if step.Validate == nil {
+		return nil
+	}
+	return step.Validate(step.Form.Values())
+}
+
+func (m WizardModel) submit() tea.Msg {
+	return WizardSubmitMsg{
+		Name:   m.name,
+		Values: m.Values(),
+	}
+}